@@ -18,6 +18,7 @@ import (
 	rpcClient "github.com/tendermint/tendermint/rpc/client"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 
+	"github.com/CyberMiles/travis/signer"
 	"github.com/CyberMiles/travis/vm/ethereum"
 	emtTypes "github.com/CyberMiles/travis/vm/types"
 	"github.com/ethereum/go-ethereum/params"
@@ -50,6 +51,9 @@ type Backend struct {
 
 	// moved from txpool.pendingState
 	managedState *state.ManagedState
+
+	// signs for the accounts not in the keystore, nil if not configured
+	extSigner *signer.External
 }
 
 // NewBackend creates a new Backend
@@ -122,6 +126,12 @@ func (b *Backend) SetTMNode(tmNode *tmn.Node) {
 	waitForServer(b.localClient)
 }
 
+// SetExternalSigner makes the cmt_* methods sign with the external signer for
+// the accounts which are not in the keystore
+func (b *Backend) SetExternalSigner(ext *signer.External) {
+	b.extSigner = ext
+}
+
 func (b *Backend) PeerCount() int {
 	var net *ctypes.ResultNetInfo
 	net, _ = b.GetLocalClient().NetInfo()
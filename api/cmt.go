@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
 
 	"github.com/spf13/cast"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 	abci "github.com/tendermint/tendermint/abci/types"
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -24,6 +26,8 @@ import (
 	ttypes "github.com/tendermint/tendermint/types"
 
 	"github.com/CyberMiles/travis/modules/governance"
+	"github.com/CyberMiles/travis/modules/multisig"
+	"github.com/CyberMiles/travis/modules/sponsor"
 	"github.com/CyberMiles/travis/modules/stake"
 	"github.com/CyberMiles/travis/sdk"
 	"github.com/CyberMiles/travis/types"
@@ -38,11 +42,13 @@ type CmtRPCService struct {
 }
 
 func NewCmtRPCService(b *Backend, nonceLock *AddrLocker) *CmtRPCService {
-	return &CmtRPCService{
+	s := &CmtRPCService{
 		backend:   b,
 		am:        b.ethereum.AccountManager(),
 		nonceLock: nonceLock,
 	}
+	governance.UpgradeReadyReporter = s.reportUpgradeReady
+	return s
 }
 
 // sign tx and broadcast commit to tendermint.
@@ -74,7 +80,7 @@ func (s *CmtRPCService) SendRawTx(encodedTx hexutil.Bytes) (*ctypes.ResultBroadc
 		return nil, err
 	}
 
-	if utils.IsEthTx(tx) {
+	if utils.IsEthTx(tx, s.nextHeight()) {
 		result, err := s.backend.BroadcastTxSync(tx)
 		if err != nil {
 			return nil, err
@@ -137,6 +143,7 @@ type RPCTransaction struct {
 	R                *hexutil.Big           `json:"r"`
 	S                *hexutil.Big           `json:"s"`
 	TxResult         abci.ResponseDeliverTx `json:"txResult"`
+	GasFee           *hexutil.Big           `json:"gasFee"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC representation.
@@ -148,7 +155,7 @@ func newRPCTransaction(res *ctypes.ResultTx) (*RPCTransaction, error) {
 	}
 
 	var travisTx sdk.Tx
-	if !utils.IsEthTx(tx) {
+	if !utils.IsEthTx(tx, res.Height) {
 		if err := json.Unmarshal(tx.Data(), &travisTx); err != nil {
 			return nil, err
 		}
@@ -160,11 +167,27 @@ func newRPCTransaction(res *ctypes.ResultTx) (*RPCTransaction, error) {
 		rpcTx.CmtHash = res.Hash
 		rpcTx.CmtInput = travisTx
 		rpcTx.TxResult = res.TxResult
+		// the actual fee charged for the travis tx
+		if !travisTx.Empty() {
+			rpcTx.GasFee = (*hexutil.Big)(gasFeeOf(res.TxResult))
+		}
 	}
 
 	return rpcTx, nil
 }
 
+// gasFeeOf reads the fee from the decimal tag, the results of the earlier blocks only have the int64 fee
+func gasFeeOf(res abci.ResponseDeliverTx) *big.Int {
+	for _, tag := range res.Tags {
+		if string(tag.Key) == sdk.GasFeeTag {
+			if fee, ok := new(big.Int).SetString(string(tag.Value), 10); ok {
+				return fee
+			}
+		}
+	}
+	return big.NewInt(res.Fee.Value)
+}
+
 // GetTransactionFromBlock returns the transaction for the given block number and index.
 func (s *CmtRPCService) GetTransactionFromBlock(height uint64, index uint64) (*RPCTransaction, error) {
 	// get block
@@ -282,25 +305,78 @@ func (s *CmtRPCService) makeTravisTxArgs(tx sdk.Tx, address common.Address, nonc
 		return nil, err
 	}
 
-	zeroUint := hexutil.Uint64(0)
-	zeroBigInt := big.NewInt(0)
+	// declare the gas required by the tx, once the chain charges the declared gas
+	gas := hexutil.Uint64(0)
+	gasPrice := big.NewInt(0)
+	if utils.IsDeclaredGas(s.nextHeight()) {
+		gas = hexutil.Uint64(travisTxGas(tx))
+		gasPrice.SetUint64(utils.GetParams().GasPrice)
+	}
 	return &SendTxArgs{
 		address,
 		nil,
-		&zeroUint,
-		(*hexutil.Big)(zeroBigInt),
-		(*hexutil.Big)(zeroBigInt),
+		&gas,
+		(*hexutil.Big)(gasPrice),
+		(*hexutil.Big)(big.NewInt(0)),
 		nonce,
 		(*hexutil.Bytes)(&data),
 		nil,
 	}, nil
 }
 
+// travisTxGas returns the gas the tx is charged by its module
+func travisTxGas(tx sdk.Tx) uint64 {
+	params := utils.GetParams()
+	switch tx.Unwrap().(type) {
+	case stake.TxDeclareCandidacy:
+		return params.DeclareCandidacyGas
+	case stake.TxUpdateCandidacy:
+		return params.UpdateCandidacyGas
+	case stake.TxSetCompRate:
+		return params.SetCompRateGas
+	case stake.TxUpdateCandidacyAccount:
+		return params.UpdateCandidateAccountGas
+	case stake.TxAcceptCandidacyAccountUpdate:
+		return params.AcceptCandidateAccountUpdateRequestGas
+	case governance.TxTransferFundPropose:
+		return params.TransferFundProposalGas
+	case governance.TxChangeParamPropose:
+		return params.ChangeParamsProposalGas
+	case governance.TxDeployLibEniPropose:
+		return params.DeployLibEniProposalGas
+	case governance.TxRetireProgramPropose:
+		return params.RetireProgramProposalGas
+	case governance.TxUpgradeProgramPropose:
+		return params.UpgradeProgramProposalGas
+	case sponsor.TxOpenSponsorship, sponsor.TxFundSponsorship, sponsor.TxSetSponsorshipLimits, sponsor.TxWithdrawSponsorship:
+		return params.SponsorshipGas
+	case multisig.TxExecuteMultisig:
+		// the executed tx is checked against the gas limit too
+		gas := params.MultisigGas
+		for _, g := range []uint64{params.DeclareCandidacyGas, params.UpdateCandidacyGas, params.UpdateCandidateAccountGas,
+			params.AcceptCandidateAccountUpdateRequestGas, params.TransferFundProposalGas, params.ChangeParamsProposalGas,
+			params.DeployLibEniProposalGas, params.RetireProgramProposalGas, params.UpgradeProgramProposalGas} {
+			if g > gas {
+				gas = g
+			}
+		}
+		return gas
+	case multisig.TxCreateMultisig, multisig.TxProposeMultisig, multisig.TxApproveMultisig:
+		return params.MultisigGas
+	}
+	return 0
+}
+
+// nextHeight returns the height of the block the txs sent now are expected in
+func (s *CmtRPCService) nextHeight() int64 {
+	return s.backend.Ethereum().BlockChain().CurrentBlock().Number().Int64() + 1
+}
+
 type DeclareCandidacyArgs struct {
 	Nonce       *hexutil.Uint64   `json:"nonce"`
 	From        common.Address    `json:"from"`
 	PubKey      string            `json:"pubKey"`
-	MaxAmount   hexutil.Big       `json:"maxAmount"`
+	MaxAmount   sdk.Coin          `json:"maxAmount"`
 	CompRate    sdk.Rat           `json:"compRate"`
 	Description stake.Description `json:"description"`
 }
@@ -310,7 +386,7 @@ func (s *CmtRPCService) DeclareCandidacy(args DeclareCandidacyArgs) (*ctypes.Res
 	if err != nil {
 		return nil, err
 	}
-	tx := stake.NewTxDeclareCandidacy(pubKey, args.MaxAmount.ToInt().String(), args.CompRate, args.Description)
+	tx := stake.NewTxDeclareCandidacy(pubKey, args.MaxAmount.Wei(), args.CompRate, args.Description)
 
 	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
 	if err != nil {
@@ -340,7 +416,7 @@ type UpdateCandidacyArgs struct {
 	Nonce       *hexutil.Uint64   `json:"nonce"`
 	From        common.Address    `json:"from"`
 	PubKey      string            `json:"pubKey"`
-	MaxAmount   *hexutil.Big      `json:"maxAmount"`
+	MaxAmount   *sdk.Coin         `json:"maxAmount"`
 	CompRate    sdk.Rat           `json:"compRate"`
 	Description stake.Description `json:"description"`
 }
@@ -348,7 +424,7 @@ type UpdateCandidacyArgs struct {
 func (s *CmtRPCService) UpdateCandidacy(args UpdateCandidacyArgs) (*ctypes.ResultBroadcastTxCommit, error) {
 	maxAmount := ""
 	if args.MaxAmount != nil {
-		maxAmount = args.MaxAmount.ToInt().String()
+		maxAmount = args.MaxAmount.Wei()
 	}
 
 	pubKey := types.PubKey{}
@@ -476,13 +552,13 @@ type DelegateArgs struct {
 	Nonce            *hexutil.Uint64 `json:"nonce"`
 	From             common.Address  `json:"from"`
 	ValidatorAddress common.Address  `json:"validatorAddress"`
-	Amount           hexutil.Big     `json:"amount"`
+	Amount           sdk.Coin        `json:"amount"`
 	CubeBatch        string          `json:"cubeBatch"`
 	Sig              string          `json:"sig"`
 }
 
 func (s *CmtRPCService) Delegate(args DelegateArgs) (*ctypes.ResultBroadcastTxCommit, error) {
-	tx := stake.NewTxDelegate(args.ValidatorAddress, args.Amount.ToInt().String(), args.CubeBatch, args.Sig, "")
+	tx := stake.NewTxDelegate(args.ValidatorAddress, args.Amount.Wei(), args.CubeBatch, args.Sig, "")
 
 	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
 	if err != nil {
@@ -496,12 +572,12 @@ type WithdrawArgs struct {
 	Nonce              *hexutil.Uint64 `json:"nonce"`
 	From               common.Address  `json:"from"`
 	ValidatorAddress   common.Address  `json:"validatorAddress"`
-	Amount             hexutil.Big     `json:"amount"`
+	Amount             sdk.Coin        `json:"amount"`
 	CompletelyWithdraw bool            `json:"completelyWithdraw"`
 }
 
 func (s *CmtRPCService) Withdraw(args WithdrawArgs) (*ctypes.ResultBroadcastTxCommit, error) {
-	tx := stake.NewTxWithdraw(args.ValidatorAddress, args.Amount.ToInt().String(), args.CompletelyWithdraw)
+	tx := stake.NewTxWithdraw(args.ValidatorAddress, args.Amount.Wei(), args.CompletelyWithdraw)
 
 	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
 	if err != nil {
@@ -523,7 +599,7 @@ func (s *CmtRPCService) QueryValidators(height uint64) (*StakeQueryResult, error
 		return nil, err
 	}
 
-	return &StakeQueryResult{h, candidates}, nil
+	return &StakeQueryResult{h, stake.NewCandidatesOutput(candidates)}, nil
 }
 
 func (s *CmtRPCService) QueryValidator(address common.Address, height uint64) (*StakeQueryResult, error) {
@@ -533,7 +609,7 @@ func (s *CmtRPCService) QueryValidator(address common.Address, height uint64) (*
 		return nil, err
 	}
 
-	return &StakeQueryResult{h, &candidate}, nil
+	return &StakeQueryResult{h, stake.NewCandidateOutput(&candidate)}, nil
 }
 
 func (s *CmtRPCService) QueryDelegator(address common.Address, height uint64) (*StakeQueryResult, error) {
@@ -543,7 +619,60 @@ func (s *CmtRPCService) QueryDelegator(address common.Address, height uint64) (*
 		return nil, err
 	}
 
-	return &StakeQueryResult{h, slotDelegates}, nil
+	return &StakeQueryResult{h, stake.NewDelegationsOutput(slotDelegates)}, nil
+}
+
+// QueryValidatorsPage returns a page of the candidates selected by the filter,
+// the next page starts at the cursor of the result
+func (s *CmtRPCService) QueryValidatorsPage(filter stake.CandidateFilter, height uint64) (*StakeQueryResult, error) {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	var page stake.CandidatesPage
+	h, err := s.getParsedFromJson("/validators/page", data, &page, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeQueryResult{h, stake.NewCandidatesPageOutput(page)}, nil
+}
+
+// QueryDelegationsPage returns a page of the delegations selected by the filter,
+// the next page starts at the cursor of the result
+func (s *CmtRPCService) QueryDelegationsPage(filter stake.DelegationFilter, height uint64) (*StakeQueryResult, error) {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	var page stake.DelegationsPage
+	h, err := s.getParsedFromJson("/delegations/page", data, &page, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeQueryResult{h, stake.NewDelegationsPageOutput(page)}, nil
+}
+
+// QueryDelegations returns a page of the delegations of the validator, with
+// the pending unstake requests of each delegator from the validator
+func (s *CmtRPCService) QueryDelegations(validatorAddress common.Address, height uint64, page *stake.ValidatorDelegationsFilter) (*StakeQueryResult, error) {
+	var filter stake.ValidatorDelegationsFilter
+	if page != nil {
+		filter = *page
+	}
+	filter.ValidatorAddress = validatorAddress.Hex()
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	var res stake.ValidatorDelegationsPage
+	h, err := s.getParsedFromJson("/validator/delegations", data, &res, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeQueryResult{h, stake.NewValidatorDelegationsPageOutput(&res)}, nil
 }
 
 func (s *CmtRPCService) QueryAwardInfos(height uint64) (*StakeQueryResult, error) {
@@ -566,12 +695,194 @@ func (s *CmtRPCService) QueryAbsentValidators(height uint64) (*StakeQueryResult,
 	return &StakeQueryResult{h, absentValidators}, nil
 }
 
+func (s *CmtRPCService) QueryValidatorSetChanges(height uint64) (*StakeQueryResult, error) {
+	var changes stake.ValidatorSetChanges
+	h, err := s.getParsedFromJson("/validatorSetChanges", []byte(strconv.FormatUint(height, 10)), &changes, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeQueryResult{h, changes}, nil
+}
+
+func (s *CmtRPCService) QueryLowPriceTxStats() (*StakeQueryResult, error) {
+	var stats interface{}
+	h, err := s.getParsedFromJson("/lowPriceTxStats", []byte{0}, &stats, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeQueryResult{h, stats}, nil
+}
+
+type OpenSponsorshipArgs struct {
+	Nonce          *hexutil.Uint64 `json:"nonce"`
+	From           common.Address  `json:"from"`
+	Contract       common.Address  `json:"contract"`
+	CreationNonce  hexutil.Uint64  `json:"creationNonce"`
+	Amount         sdk.Coin        `json:"amount"`
+	MaxFeePerUser  sdk.Coin        `json:"maxFeePerUser"`
+	MaxFeePerBlock sdk.Coin        `json:"maxFeePerBlock"`
+}
+
+func (s *CmtRPCService) OpenSponsorship(args OpenSponsorshipArgs) (*ctypes.ResultBroadcastTxCommit, error) {
+	tx := sponsor.NewTxOpenSponsorship(args.Contract, uint64(args.CreationNonce), args.Amount.Wei(),
+		args.MaxFeePerUser.Wei(), args.MaxFeePerBlock.Wei())
+
+	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signAndBroadcastTxCommit(txArgs)
+}
+
+type FundSponsorshipArgs struct {
+	Nonce    *hexutil.Uint64 `json:"nonce"`
+	From     common.Address  `json:"from"`
+	Contract common.Address  `json:"contract"`
+	Amount   sdk.Coin        `json:"amount"`
+}
+
+func (s *CmtRPCService) FundSponsorship(args FundSponsorshipArgs) (*ctypes.ResultBroadcastTxCommit, error) {
+	tx := sponsor.NewTxFundSponsorship(args.Contract, args.Amount.Wei())
+
+	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signAndBroadcastTxCommit(txArgs)
+}
+
+type SetSponsorshipLimitsArgs struct {
+	Nonce          *hexutil.Uint64 `json:"nonce"`
+	From           common.Address  `json:"from"`
+	Contract       common.Address  `json:"contract"`
+	MaxFeePerUser  sdk.Coin        `json:"maxFeePerUser"`
+	MaxFeePerBlock sdk.Coin        `json:"maxFeePerBlock"`
+}
+
+func (s *CmtRPCService) SetSponsorshipLimits(args SetSponsorshipLimitsArgs) (*ctypes.ResultBroadcastTxCommit, error) {
+	tx := sponsor.NewTxSetSponsorshipLimits(args.Contract, args.MaxFeePerUser.Wei(), args.MaxFeePerBlock.Wei())
+
+	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signAndBroadcastTxCommit(txArgs)
+}
+
+type WithdrawSponsorshipArgs struct {
+	Nonce    *hexutil.Uint64 `json:"nonce"`
+	From     common.Address  `json:"from"`
+	Contract common.Address  `json:"contract"`
+	Amount   sdk.Coin        `json:"amount"`
+}
+
+func (s *CmtRPCService) WithdrawSponsorship(args WithdrawSponsorshipArgs) (*ctypes.ResultBroadcastTxCommit, error) {
+	tx := sponsor.NewTxWithdrawSponsorship(args.Contract, args.Amount.Wei())
+
+	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signAndBroadcastTxCommit(txArgs)
+}
+
+func (s *CmtRPCService) QuerySponsorship(contract common.Address, height uint64) (*StakeQueryResult, error) {
+	var sponsorship sponsor.Sponsorship
+	h, err := s.getParsedFromJson("/sponsorship", []byte(contract.Hex()), &sponsorship, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeQueryResult{h, &sponsorship}, nil
+}
+
+type CreateMultisigArgs struct {
+	Nonce     *hexutil.Uint64  `json:"nonce"`
+	From      common.Address   `json:"from"`
+	Members   []common.Address `json:"members"`
+	Threshold hexutil.Uint64   `json:"threshold"`
+}
+
+func (s *CmtRPCService) CreateMultisig(args CreateMultisigArgs) (*ctypes.ResultBroadcastTxCommit, error) {
+	tx := multisig.NewTxCreateMultisig(args.Members, int(args.Threshold))
+
+	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signAndBroadcastTxCommit(txArgs)
+}
+
+type ProposeMultisigArgs struct {
+	Nonce    *hexutil.Uint64 `json:"nonce"`
+	From     common.Address  `json:"from"`
+	Multisig common.Address  `json:"multisig"`
+	Tx       sdk.Tx          `json:"tx"`
+}
+
+func (s *CmtRPCService) ProposeMultisig(args ProposeMultisigArgs) (*ctypes.ResultBroadcastTxCommit, error) {
+	tx := multisig.NewTxProposeMultisig(args.Multisig, args.Tx)
+
+	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signAndBroadcastTxCommit(txArgs)
+}
+
+type MultisigTxArgs struct {
+	Nonce    *hexutil.Uint64 `json:"nonce"`
+	From     common.Address  `json:"from"`
+	Multisig common.Address  `json:"multisig"`
+	Id       hexutil.Uint64  `json:"id"`
+}
+
+func (s *CmtRPCService) ApproveMultisig(args MultisigTxArgs) (*ctypes.ResultBroadcastTxCommit, error) {
+	tx := multisig.NewTxApproveMultisig(args.Multisig, uint64(args.Id))
+
+	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signAndBroadcastTxCommit(txArgs)
+}
+
+func (s *CmtRPCService) ExecuteMultisig(args MultisigTxArgs) (*ctypes.ResultBroadcastTxCommit, error) {
+	tx := multisig.NewTxExecuteMultisig(args.Multisig, uint64(args.Id))
+
+	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signAndBroadcastTxCommit(txArgs)
+}
+
+func (s *CmtRPCService) QueryMultisig(address common.Address, height uint64) (*StakeQueryResult, error) {
+	var info multisig.MultisigInfo
+	h, err := s.getParsedFromJson("/multisig", []byte(address.Hex()), &info, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeQueryResult{h, &info}, nil
+}
+
 type GovernanceTransferFundProposalArgs struct {
 	Nonce             *hexutil.Uint64 `json:"nonce"`
 	From              common.Address  `json:"from"`
 	TransferFrom      common.Address  `json:"transferFrom"`
 	TransferTo        common.Address  `json:"transferTo"`
-	Amount            hexutil.Big     `json:"amount"`
+	Amount            sdk.Coin        `json:"amount"`
 	Reason            string          `json:"reason"`
 	ExpireTimestamp   *int64          `json:"expireTimestamp"`
 	ExpireBlockHeight *int64          `json:"expireBlockHeight"`
@@ -579,7 +890,7 @@ type GovernanceTransferFundProposalArgs struct {
 
 func (s *CmtRPCService) ProposeTransferFund(args GovernanceTransferFundProposalArgs) (*ctypes.ResultBroadcastTxCommit, error) {
 	tx := governance.NewTxTransferFundPropose(&args.TransferFrom, &args.TransferTo,
-		args.Amount.ToInt().String(), args.Reason,
+		args.Amount.Wei(), args.Reason,
 		args.ExpireTimestamp, args.ExpireBlockHeight)
 
 	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
@@ -696,6 +1007,45 @@ func (s *CmtRPCService) Vote(args GovernanceVoteArgs) (*ctypes.ResultBroadcastTx
 	return s.signAndBroadcastTxCommit(txArgs)
 }
 
+type GovernanceUpgradeReadyArgs struct {
+	Nonce      *hexutil.Uint64 `json:"nonce"`
+	From       common.Address  `json:"from"`
+	ProposalId string          `json:"proposalId"`
+}
+
+// SignalUpgradeReady tells the chain the validator has staged the new program version of the proposal
+func (s *CmtRPCService) SignalUpgradeReady(args GovernanceUpgradeReadyArgs) (*ctypes.ResultBroadcastTxCommit, error) {
+	tx := governance.NewTxUpgradeReady(args.ProposalId)
+
+	txArgs, err := s.makeTravisTxArgs(tx, args.From, args.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signAndBroadcastTxCommit(txArgs)
+}
+
+// reportUpgradeReady signals the readiness with the validator accounts of the local keystore,
+// the accounts must be unlocked
+func (s *CmtRPCService) reportUpgradeReady(p *governance.Proposal) {
+	validators := stake.QueryCandidates().Validators()
+	for _, wallet := range s.am.Wallets() {
+		for _, account := range wallet.Accounts() {
+			for _, v := range validators {
+				if v.OwnerAddress != account.Address.String() {
+					continue
+				}
+				_, err := s.SignalUpgradeReady(GovernanceUpgradeReadyArgs{From: account.Address, ProposalId: p.Id})
+				if err != nil {
+					log.Warn("Failed to signal upgrade readiness", "proposal", p.Id, "validator", account.Address.Hex(), "err", err)
+				} else {
+					log.Info("Signaled upgrade readiness", "proposal", p.Id, "validator", account.Address.Hex())
+				}
+			}
+		}
+	}
+}
+
 func (s *CmtRPCService) QueryProposals() (*StakeQueryResult, error) {
 	var proposals []*governance.Proposal
 	h, err := s.getParsedFromJson("/governance/proposals", []byte{0}, &proposals, 0)
@@ -706,6 +1056,16 @@ func (s *CmtRPCService) QueryProposals() (*StakeQueryResult, error) {
 	return &StakeQueryResult{h, proposals}, nil
 }
 
+func (s *CmtRPCService) QueryUpgradeStatus(pid string) (*StakeQueryResult, error) {
+	var status governance.UpgradeStatus
+	h, err := s.getParsedFromJson("/governance/upgradeStatus", []byte(pid), &status, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeQueryResult{h, &status}, nil
+}
+
 func (s *CmtRPCService) QueryParams(height uint64) (*StakeQueryResult, error) {
 	var params utils.Params
 	h, err := s.getParsedFromJson("/key", utils.ParamKey, &params, height)
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/CyberMiles/travis/types"
+)
+
+// Events creates a subscription that is triggered each time a staking or
+// governance event matching the filter is committed, e.g.
+// cmt_subscribe("events", {"types": ["slash"], "addresses": ["0x..."]})
+func (s *CmtRPCService) Events(ctx context.Context, filter types.EventFilter) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	sub := types.EventBus.Subscribe(filter)
+
+	go func() {
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev, ok := <-sub.Chan():
+				if !ok {
+					return
+				}
+				notifier.Notify(rpcSub.ID, ev)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
@@ -15,11 +15,14 @@ import (
 	rpcClient "github.com/tendermint/tendermint/rpc/client"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 
+	"github.com/CyberMiles/travis/server"
 	"github.com/CyberMiles/travis/utils"
 )
 
 const defaultGas = 90000
 
+var errShuttingDown = errors.New("the node is shutting down")
+
 // SendTxArgs represents the arguments to sumbit a new transaction
 type SendTxArgs struct {
 	From     common.Address  `json:"from"`
@@ -86,6 +89,9 @@ func (args *SendTxArgs) toTransaction() *ethTypes.Transaction {
 // BroadcastTx broadcasts a transaction to tendermint core
 // #unstable
 func (b *Backend) BroadcastTxSync(tx *ethTypes.Transaction) (*ctypes.ResultBroadcastTx, error) {
+	if server.ShuttingDown() {
+		return nil, errShuttingDown
+	}
 	buf := new(bytes.Buffer)
 	if err := tx.EncodeRLP(buf); err != nil {
 		return nil, err
@@ -95,6 +101,9 @@ func (b *Backend) BroadcastTxSync(tx *ethTypes.Transaction) (*ctypes.ResultBroad
 }
 
 func (b *Backend) BroadcastTxCommit(tx *ethTypes.Transaction) (*ctypes.ResultBroadcastTxCommit, error) {
+	if server.ShuttingDown() {
+		return nil, errShuttingDown
+	}
 	buf := new(bytes.Buffer)
 	if err := tx.EncodeRLP(buf); err != nil {
 		return nil, err
@@ -116,11 +125,15 @@ func (b *Backend) signTransaction(args *SendTxArgs) (*ethTypes.Transaction, erro
 	// Assemble the transaction and sign with the wallet
 	tx := args.toTransaction()
 
+	ethChainId := int64(b.ethConfig.NetworkId)
 	wallet, err := b.ethereum.AccountManager().Find(account)
 	if err != nil {
+		// the key may be held by the external signer
+		if b.extSigner != nil {
+			return b.extSigner.SignTx(args.From, tx, big.NewInt(ethChainId))
+		}
 		return nil, err
 	}
-	ethChainId := int64(b.ethConfig.NetworkId)
 	signed, err := wallet.SignTx(account, tx, big.NewInt(ethChainId))
 	if err != nil {
 		return nil, err
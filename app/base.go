@@ -1,13 +1,17 @@
 package app
 
 import (
-	"bytes"
 	"database/sql"
+	"encoding/json"
 	goerr "errors"
 	"math/big"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/CyberMiles/travis/metrics"
 	"github.com/CyberMiles/travis/modules/governance"
+	"github.com/CyberMiles/travis/modules/sponsor"
 	"github.com/CyberMiles/travis/modules/stake"
 	"github.com/CyberMiles/travis/sdk"
 	"github.com/CyberMiles/travis/sdk/dbm"
@@ -24,7 +28,6 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/tendermint/tendermint/crypto/ed25519"
-	"golang.org/x/crypto/ripemd160"
 )
 
 // BaseApp - The ABCI application
@@ -40,8 +43,16 @@ type BaseApp struct {
 	blockTime           int64
 	deliverSqlTx        *sql.Tx
 	proposer            abci.Validator
+	appHashes           map[int64][]byte // app hashes of the recent blocks, checked by the health endpoint
+	appHashMtx          sync.RWMutex
+	blockSem            chan struct{} // held from BeginBlock to Commit, taken for good by Halt
+	halted              bool          // the block in flight is rolled back at its commit
+	haltMtx             sync.Mutex    // held during the commit
 }
 
+// number of the recent app hashes kept
+const appHashesKept = 100
+
 var (
 	_            abci.Application = &BaseApp{}
 	toBeShutdown                  = false
@@ -83,6 +94,8 @@ func NewBaseApp(store *StoreApp, ethApp *EthermintApplication, ethereum *eth.Eth
 		EthApp:    ethApp,
 		checkedTx: make(map[common.Hash]*types.Transaction),
 		ethereum:  ethereum,
+		appHashes: make(map[int64][]byte),
+		blockSem:  make(chan struct{}, 1),
 	}
 	return app, nil
 }
@@ -123,18 +136,34 @@ func (app *BaseApp) Info(req abci.RequestInfo) abci.ResponseInfo {
 	}
 
 	travisInfoRes := app.StoreApp.Info(req)
+	travisInfoRes.LastBlockAppHash = app.lastAppHash(ethInfoRes, travisInfoRes)
+	return travisInfoRes
+}
 
+// lastAppHash returns the app hash of the last committed block
+func (app *BaseApp) lastAppHash(ethInfoRes, travisInfoRes abci.ResponseInfo) []byte {
 	// If the chain has just relaunched from a retired version,
 	// then use the old algorithm to match the old hash
 	var travisDbHash []byte
-	if governance.GetLatestRetiredHeight() == lbh {
+	if governance.GetLatestRetiredHeight() == ethInfoRes.LastBlockHeight {
 		travisDbHash = app.StoreApp.GetOldDbHash()
 	} else {
 		travisDbHash = app.StoreApp.GetDbHash()
 	}
 
-	travisInfoRes.LastBlockAppHash = finalAppHash(ethInfoRes.LastBlockAppHash, travisInfoRes.LastBlockAppHash, travisDbHash, travisInfoRes.LastBlockHeight, nil)
-	return travisInfoRes
+	return finalAppHash(ethInfoRes.LastBlockAppHash, travisInfoRes.LastBlockAppHash, travisDbHash, travisInfoRes.LastBlockHeight, nil)
+}
+
+// Query - ABCI
+func (app *BaseApp) Query(reqQuery abci.RequestQuery) abci.ResponseQuery {
+	if reqQuery.Path == "/lowPriceTxStats" {
+		b, _ := json.Marshal(app.EthApp.LowPriceTxStats())
+		return abci.ResponseQuery{Value: b, Height: app.CommittedHeight()}
+	}
+	if reqQuery.Prove && provable(reqQuery.Path) {
+		return app.queryWithProof(reqQuery)
+	}
+	return app.StoreApp.Query(reqQuery)
 }
 
 // DeliverTx - ABCI
@@ -145,21 +174,37 @@ func (app *BaseApp) DeliverTx(txBytes []byte) abci.ResponseDeliverTx {
 		return errors.DeliverResult(err)
 	}
 
-	if utils.IsEthTx(tx) {
+	if routeOf(tx, app.WorkingHeight()) == ethRoute {
 		if checkedTx, ok := app.checkedTx[tx.Hash()]; ok {
 			tx = checkedTx
-		} else {
-			// force cache from of tx
-			networkId := big.NewInt(int64(app.ethereum.NetVersion()))
-			signer := types.NewEIP155Signer(networkId)
+		}
+		// force cache from of tx
+		networkId := big.NewInt(int64(app.ethereum.NetVersion()))
+		signer := types.NewEIP155Signer(networkId)
 
-			if _, err := types.Sender(signer, tx); err != nil {
-				app.logger.Debug("DeliverTx: Received invalid transaction", "tx", tx, "err", err)
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			app.logger.Debug("DeliverTx: Received invalid transaction", "tx", tx, "err", err)
+			return errors.DeliverResult(err)
+		}
+
+		sponsored := utils.IsSponsoredTx(tx)
+		if sponsored {
+			maxFee := new(big.Int).Mul(new(big.Int).SetUint64(utils.GetParams().GasPrice), new(big.Int).SetUint64(tx.Gas()))
+			if err := sponsor.CheckSponsoredTx(app.Append(), *tx.To(), from, maxFee, app.WorkingHeight()); err != nil {
 				return errors.DeliverResult(err)
 			}
 		}
+
 		resp := app.EthApp.DeliverTx(tx)
 		app.logger.Debug("EthApp DeliverTx response", "resp", resp)
+
+		// charge the callee contract for the gas used at the default gas price
+		if sponsored && resp.Code == abci.CodeTypeOK {
+			fee := new(big.Int).Mul(new(big.Int).SetUint64(utils.GetParams().GasPrice), big.NewInt(resp.GasUsed))
+			sponsor.ChargeSponsoredTx(app.Append(), app.EthApp.DeliverTxState(), *tx.To(), from, fee, app.WorkingHeight())
+			app.TotalUsedGasFee.Add(app.TotalUsedGasFee, fee)
+		}
 		return resp
 	}
 
@@ -177,12 +222,32 @@ func (app *BaseApp) CheckTx(txBytes []byte) abci.ResponseCheckTx {
 		return errors.CheckResult(err)
 	}
 
-	if utils.IsEthTx(tx) {
+	if routeOf(tx, app.WorkingHeight()) == ethRoute {
+		var from common.Address
+		var maxFee *big.Int
+		sponsored := utils.IsSponsoredTx(tx)
+		if sponsored {
+			networkId := big.NewInt(int64(app.ethereum.NetVersion()))
+			from, err = types.Sender(types.NewEIP155Signer(networkId), tx)
+			if err != nil {
+				return errors.CheckResult(err)
+			}
+			maxFee = new(big.Int).Mul(new(big.Int).SetUint64(utils.GetParams().GasPrice), new(big.Int).SetUint64(tx.Gas()))
+			if err := sponsor.CheckSponsoredTx(app.Check(), *tx.To(), from, maxFee, app.WorkingHeight()); err != nil {
+				return errors.CheckResult(err)
+			}
+		}
+
 		resp := app.EthApp.CheckTx(tx)
 		app.logger.Debug("EthApp CheckTx response", "resp", resp)
 		if resp.IsErr() {
 			return errors.CheckResult(goerr.New(resp.String()))
 		}
+
+		// reserve the maximum fee so that the mempool does not exceed the sponsorship limits
+		if sponsored {
+			sponsor.ChargeSponsoredTx(app.Check(), app.EthApp.checkTxState, *tx.To(), from, maxFee, app.WorkingHeight())
+		}
 		app.checkedTx[tx.Hash()] = tx
 		return sdk.NewCheck(0, "").ToABCI()
 	}
@@ -195,6 +260,8 @@ func (app *BaseApp) CheckTx(txBytes []byte) abci.ResponseCheckTx {
 
 // BeginBlock - ABCI
 func (app *BaseApp) BeginBlock(req abci.RequestBeginBlock) (res abci.ResponseBeginBlock) {
+	// blocks forever once the node is halted
+	app.blockSem <- struct{}{}
 	app.blockTime = req.GetHeader().Time
 	app.EthApp.BeginBlock(req)
 	app.PresentValidators = app.PresentValidators[:0]
@@ -246,6 +313,9 @@ func (app *BaseApp) getAbsentValidators(req abci.RequestBeginBlock) {
 		pubKey := ttypes.PubKey{pk}
 		if !sv.SignedLastBlock {
 			app.AbsentValidators.Add(pubKey, app.WorkingHeight())
+			if metrics.Enabled() {
+				metrics.AbsentBlocks.WithLabelValues(ttypes.PubKeyString(pubKey)).Inc()
+			}
 		}
 	}
 }
@@ -318,7 +388,7 @@ func (app *BaseApp) EndBlock(req abci.RequestEndBlock) (res abci.ResponseEndBloc
 				}
 			}
 			if pvSize >= 1 {
-				inaVs.Deactivate()
+				inaVs.Deactivate(app.WorkingHeight())
 				app.AddValChange(abciVs)
 				toBeShutdown = true
 				governance.UpdateRetireProgramStatus(utils.RetiringProposalId, "success")
@@ -350,10 +420,45 @@ func (app *BaseApp) EndBlock(req abci.RequestEndBlock) (res abci.ResponseEndBloc
 	// handle the pending unstake requests
 	stake.HandlePendingUnstakeRequests(app.WorkingHeight())
 
+	if metrics.Enabled() {
+		recordMetrics()
+	}
+
 	return app.StoreApp.EndBlock(req)
 }
 
+// recordMetrics sets the gauges of the staking and the governance at the end of the block
+func recordMetrics() {
+	metrics.ValidatorVotingPower.Reset()
+	metrics.ValidatorState.Reset()
+	for _, c := range stake.GetActiveCandidates() {
+		metrics.ValidatorVotingPower.WithLabelValues(c.OwnerAddress).Set(float64(c.VotingPower))
+		metrics.ValidatorState.WithLabelValues(c.OwnerAddress, c.State).Set(1)
+	}
+
+	metrics.PendingUnstake.Set(metrics.ToCmt(stake.GetPendingUnstakeAmount()))
+
+	metrics.Proposals.Reset()
+	for result, count := range governance.CountProposalsByResult() {
+		if result == "" {
+			result = "Pending"
+		}
+		metrics.Proposals.WithLabelValues(result).Set(float64(count))
+	}
+}
+
 func (app *BaseApp) Commit() (res abci.ResponseCommit) {
+	// held until the block is committed, the halt waits for it before the stores are closed
+	app.haltMtx.Lock()
+	if app.halted {
+		// the block isn't committed, tendermint replays it at the restart
+		if app.deliverSqlTx != nil {
+			app.deliverSqlTx.Rollback()
+		}
+		app.haltMtx.Unlock()
+		select {}
+	}
+
 	if toBeShutdown {
 		server.StopFlag <- true
 	}
@@ -370,6 +475,7 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 			stake.ResetDeliverSqlTx()
 			governance.ResetDeliverSqlTx()
 		}
+		ttypes.EventBus.Discard()
 
 		// slash block proposer
 		var pk ed25519.PubKeyEd25519
@@ -379,10 +485,17 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 	} else {
 		if app.deliverSqlTx != nil {
 			// Commit transaction
+			if err := saveSqlHeight(app.deliverSqlTx, app.WorkingHeight()); err != nil {
+				panic(err)
+			}
+			start := time.Now()
 			err := app.deliverSqlTx.Commit()
 			if err != nil {
 				panic(err)
 			}
+			if metrics.Enabled() {
+				metrics.SqlCommitSeconds.Observe(time.Since(start).Seconds())
+			}
 			stake.ResetDeliverSqlTx()
 			governance.ResetDeliverSqlTx()
 		}
@@ -398,23 +511,70 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 	// reset store app
 	app.TotalUsedGasFee = big.NewInt(0)
 
-	res = app.StoreApp.Commit()
 	dbHash := app.StoreApp.GetDbHash()
+	if h := utils.GetParams().StateProofHeight; h > 0 && workingHeight >= int64(h) {
+		// the rows are mirrored along with the hash of the db, so the proofs lead to the app hash,
+		// all of them at the first height and then only the ones changed by the block
+		state := app.Append()
+		seed := !state.Has(utils.DbHashKey)
+		stake.MirrorState(state, seed)
+		governance.MirrorState(state, seed)
+		state.Set(utils.DbHashKey, dbHash)
+	}
+
+	res = app.StoreApp.Commit()
 	res.Data = finalAppHash(ethAppCommit.Data, res.Data, dbHash, workingHeight, nil)
 
+	app.appHashMtx.Lock()
+	app.appHashes[workingHeight] = res.Data
+	delete(app.appHashes, workingHeight-appHashesKept)
+	app.appHashMtx.Unlock()
+
+	// the block started by BeginBlock is committed, the next one can start
+	select {
+	case <-app.blockSem:
+	default:
+	}
+	app.haltMtx.Unlock()
+
+	// notify the subscribers of the events happened in this block
+	ttypes.EventBus.Flush()
+
 	return
 }
 
-func finalAppHash(ethCommitHash []byte, travisCommitHash []byte, dbHash []byte, workingHeight int64, store *state.SimpleDB) []byte {
+// Halt waits for the block in flight to be committed and keeps any other from
+// starting. If its commit hasn't started in time, the abci goroutine rolls it
+// back instead of committing it, a commit in progress is always waited for.
+func (app *BaseApp) Halt(timeout time.Duration) bool {
+	select {
+	case app.blockSem <- struct{}{}:
+		return true
+	case <-time.After(timeout):
+	}
+
+	app.haltMtx.Lock()
+	defer app.haltMtx.Unlock()
+	// the block may have been committed in the meantime
+	select {
+	case app.blockSem <- struct{}{}:
+		return true
+	default:
+	}
+	app.halted = true
+	return false
+}
 
-	hasher := ripemd160.New()
-	buf := new(bytes.Buffer)
-	buf.Write(ethCommitHash)
-	buf.Write(travisCommitHash)
-	buf.Write(dbHash)
-	hasher.Write(buf.Bytes())
-	hash := hasher.Sum(nil)
-	return hash
+// CommittedAppHash returns the app hash committed at the height, only the recent ones are kept
+func (app *BaseApp) CommittedAppHash(height int64) ([]byte, bool) {
+	app.appHashMtx.RLock()
+	defer app.appHashMtx.RUnlock()
+	hash, ok := app.appHashes[height]
+	return hash, ok
+}
+
+func finalAppHash(ethCommitHash []byte, travisCommitHash []byte, dbHash []byte, workingHeight int64, store *state.SimpleDB) []byte {
+	return state.AppHash(ethCommitHash, travisCommitHash, dbHash)
 }
 
 func calVPCheck(height int64) bool {
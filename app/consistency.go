@@ -0,0 +1,74 @@
+package app
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// saveSqlHeight records the height in the transaction of the block, so the
+// height of the sqlite db is known at the restart
+func saveSqlHeight(tx *sql.Tx, height int64) error {
+	_, err := tx.Exec("insert or replace into app_state(id, block_height) values(1, ?)", height)
+	return err
+}
+
+// loadSqlHeight returns the height the sqlite db was last committed at, it is
+// unknown until the first block committed by a node recording it
+func loadSqlHeight(db *sql.DB) (height int64, ok bool, err error) {
+	err = db.QueryRow("select block_height from app_state where id = 1").Scan(&height)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	return height, err == nil, err
+}
+
+// CheckStores makes sure the vm state, the sqlite db and the iavl store were
+// committed at the same height and that their app hash is the one tendermint
+// recorded for it. A crash during a commit leaves the store committed last,
+// the iavl one, behind, tendermint would replay the block onto the others.
+func (app *BaseApp) CheckStores(db *sql.DB, tmHeight int64, tmAppHash []byte) error {
+	ethInfoRes := app.EthApp.Info(abci.RequestInfo{})
+	vmHeight := ethInfoRes.LastBlockHeight
+	iavlHeight := app.CommittedHeight()
+	sqlHeight, ok, err := loadSqlHeight(db)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		sqlHeight = iavlHeight
+	}
+
+	if vmHeight != sqlHeight || sqlHeight != iavlHeight {
+		var behind []string
+		top := max64(vmHeight, max64(sqlHeight, iavlHeight))
+		for _, s := range []struct {
+			name   string
+			height int64
+		}{{"vm state", vmHeight}, {"sqlite db", sqlHeight}, {"iavl store", iavlHeight}} {
+			if s.height < top {
+				behind = append(behind, fmt.Sprintf("the %s is behind at height %d", s.name, s.height))
+			}
+		}
+		return fmt.Errorf("the stores were not committed at the same height, %s, the others are at height %d, restore the data directory from a backup or a snapshot", strings.Join(behind, ", "), top)
+	}
+
+	// tendermint replays the blocks the app misses, the app hash can only be compared at the same height
+	if iavlHeight > 0 && tmHeight == iavlHeight {
+		appHash := app.lastAppHash(ethInfoRes, app.StoreApp.Info(abci.RequestInfo{}))
+		if !bytes.Equal(appHash, tmAppHash) {
+			return fmt.Errorf("the app hash %X at height %d is not the one tendermint recorded, %X, the sqlite db doesn't match the iavl store and the vm state, restore the data directory from a backup or a snapshot", appHash, iavlHeight, tmAppHash)
+		}
+	}
+	return nil
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -42,7 +42,7 @@ type EthermintApplication struct {
 
 	logger tmLog.Logger
 
-	lowPriceCheckTransactions   map[FromTo]struct{}
+	lowPriceTxPolicy            *lowPriceTxPolicy
 	lowPriceDeliverTransactions map[FromTo]struct{}
 }
 
@@ -61,7 +61,7 @@ func NewEthermintApplication(backend *api.Backend,
 		rpcClient:                   client,
 		checkTxState:                state.StateDB,
 		strategy:                    strategy,
-		lowPriceCheckTransactions:   make(map[FromTo]struct{}),
+		lowPriceTxPolicy:            newLowPriceTxPolicy(),
 		lowPriceDeliverTransactions: make(map[FromTo]struct{}),
 	}
 
@@ -165,7 +165,8 @@ func (app *EthermintApplication) DeliverTx(tx *ethTypes.Transaction) abciTypes.R
 	app.CollectTx(tx)
 
 	return abciTypes.ResponseDeliverTx{
-		Code: abciTypes.CodeTypeOK,
+		Code:    abciTypes.CodeTypeOK,
+		GasUsed: res.GasUsed,
 	}
 }
 
@@ -218,7 +219,6 @@ func (app *EthermintApplication) Commit() (abciTypes.ResponseCommit, error) {
 	}
 	app.checkTxState = state.StateDB
 
-	app.lowPriceCheckTransactions = make(map[FromTo]struct{})
 	app.lowPriceDeliverTransactions = make(map[FromTo]struct{})
 
 	return abciTypes.ResponseCommit{
@@ -277,14 +277,14 @@ func (app *EthermintApplication) validateTx(tx *ethTypes.Transaction) abciTypes.
 	currentBalance := currentState.GetBalance(from)
 
 	// This check don't do anything
-	// It only filter the tx which qualified the freegas requirement
-	if tx.GasPrice().Int64() == 0 && tx.Gas() > utils.GetParams().LowPriceTxGasLimit && 
-		tx.To() != nil && len(tx.Data()) > 0 {
+	// It only filter the tx which qualified the freegas requirement,
+	// until the sponsorships replace them
+	if utils.IsFreeGasTx(tx) && !utils.IsSponsoredTx(tx) {
 		if currentState.GetBalance(*tx.To()).Cmp(defaultCost) < 0 {
 			return abciTypes.ResponseCheckTx{
 				// TODO: Add errors.CodeTypeInsufficientFunds ?
 				Code: errors.CodeHighGasLimitErr,
-				Log: "The gas limit is too high for low price transaction",
+				Log:  "The gas limit is too high for low price transaction",
 			}
 		}
 	} else {
@@ -298,8 +298,13 @@ func (app *EthermintApplication) validateTx(tx *ethTypes.Transaction) abciTypes.
 					currentBalance, tx.Cost())}
 		}
 
-		if code, errLog := app.lowPriceTxCheck(from, tx, app.lowPriceCheckTransactions); code != abciTypes.CodeTypeOK {
-			return abciTypes.ResponseCheckTx{Code: code, Log: errLog}
+		// The gas of the sponsored txs is paid by the callee contract,
+		// the sponsorship has been checked by the BaseApp
+		if !utils.IsSponsoredTx(tx) {
+			height := app.backend.Ethereum().BlockChain().CurrentBlock().Number().Int64()
+			if code, errLog := app.lowPriceTxPolicy.check(from, tx, height, app.backend.GasLimit()); code != abciTypes.CodeTypeOK {
+				return abciTypes.ResponseCheckTx{Code: code, Log: errLog}
+			}
 		}
 	}
 
@@ -342,3 +347,8 @@ func (app *EthermintApplication) lowPriceTxCheck(from common.Address, tx *ethTyp
 
 	return abciTypes.CodeTypeOK, ""
 }
+
+// LowPriceTxStats returns the statistics of the low-price transaction lane
+func (app *EthermintApplication) LowPriceTxStats() LowPriceTxStats {
+	return app.lowPriceTxPolicy.stats()
+}
@@ -8,11 +8,14 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/CyberMiles/travis/modules/governance"
+	"github.com/CyberMiles/travis/modules/multisig"
+	"github.com/CyberMiles/travis/modules/sponsor"
 	"github.com/CyberMiles/travis/modules/stake"
 	"github.com/CyberMiles/travis/sdk"
 	"github.com/CyberMiles/travis/sdk/errors"
 	"github.com/CyberMiles/travis/sdk/state"
 	"github.com/CyberMiles/travis/types"
+	"github.com/CyberMiles/travis/utils"
 )
 
 func (app BaseApp) checkHandler(ctx types.Context, store state.SimpleDB, tx *ethTypes.Transaction) abci.ResponseCheckTx {
@@ -22,6 +25,9 @@ func (app BaseApp) checkHandler(ctx types.Context, store state.SimpleDB, tx *eth
 	}
 	ctx.WithSigners(from)
 	ctx.SetNonce(nonce)
+	if err := ctx.SetGas(tx.Gas(), tx.GasPrice()); err != nil {
+		return errors.CheckResult(err)
+	}
 
 	var travisTx sdk.Tx
 	if err := json.Unmarshal(tx.Data(), &travisTx); err != nil {
@@ -38,6 +44,10 @@ func (app BaseApp) checkHandler(ctx types.Context, store state.SimpleDB, tx *eth
 		res, err = stake.CheckTx(ctx, store, travisTx)
 	} else if name == "governance" {
 		res, err = governance.CheckTx(ctx, store, travisTx)
+	} else if name == "sponsor" {
+		res, err = sponsor.CheckTx(ctx, store, travisTx)
+	} else if name == "multisig" {
+		res, err = multisig.CheckTx(ctx, store, travisTx)
 	}
 
 	if err != nil {
@@ -46,6 +56,12 @@ func (app BaseApp) checkHandler(ctx types.Context, store state.SimpleDB, tx *eth
 
 	currentState.SetNonce(from, nonce+1)
 
+	// the travis txs declaring their gas take the room of the low-price txs once the block is full
+	if !ctx.IsLegacyGas() {
+		height := app.EthApp.backend.Ethereum().BlockChain().CurrentBlock().Number().Int64()
+		app.EthApp.lowPriceTxPolicy.checkPaid(ctx.GasLimit(), tx.GasPrice(), height, app.EthApp.backend.GasLimit())
+	}
+
 	return res.ToABCI()
 }
 
@@ -69,6 +85,9 @@ func (app BaseApp) deliverHandler(ctx types.Context, store state.SimpleDB, tx *e
 
 	ctx.WithSigners(from)
 	ctx.SetNonce(tx.Nonce())
+	if err := ctx.SetGas(tx.Gas(), tx.GasPrice()); err != nil {
+		return errors.DeliverResult(err)
+	}
 
 	name, err := lookupRoute(travisTx)
 	if err != nil {
@@ -81,6 +100,10 @@ func (app BaseApp) deliverHandler(ctx types.Context, store state.SimpleDB, tx *e
 		res, err = stake.DeliverTx(ctx, store, travisTx, hash)
 	case "governance":
 		res, err = governance.DeliverTx(ctx, store, travisTx, hash)
+	case "sponsor":
+		res, err = sponsor.DeliverTx(ctx, store, travisTx, hash)
+	case "multisig":
+		res, err = multisig.DeliverTx(ctx, store, travisTx, hash, multisigExecutor(store, hash))
 	default:
 		return errors.DeliverResult(errors.ErrUnknownTxType(travisTx.Unwrap()))
 	}
@@ -94,6 +117,40 @@ func (app BaseApp) deliverHandler(ctx types.Context, store state.SimpleDB, tx *e
 	return res.ToABCI()
 }
 
+// multisigExecutor delivers the txs sent by the multisig accounts
+func multisigExecutor(store state.SimpleDB, hash []byte) multisig.Executor {
+	return func(ctx types.Context, tx sdk.Tx) (sdk.DeliverResult, error) {
+		name, err := lookupRoute(tx)
+		if err != nil {
+			return sdk.DeliverResult{}, err
+		}
+		switch name {
+		case "stake":
+			return stake.DeliverTx(ctx, store, tx, hash)
+		case "governance":
+			return governance.DeliverTx(ctx, store, tx, hash)
+		}
+		return sdk.DeliverResult{}, errors.ErrUnknownTxType(tx.Unwrap())
+	}
+}
+
+// ethRoute is the route of the txs run by the evm
+const ethRoute = "ethereum"
+
+// routeOf returns the name of the module handling the tx of the block,
+// ethRoute for the ethereum txs
+func routeOf(tx *ethTypes.Transaction, height int64) string {
+	if utils.IsEthTx(tx, height) {
+		return ethRoute
+	}
+	var travisTx sdk.Tx
+	if err := json.Unmarshal(tx.Data(), &travisTx); err != nil {
+		return ""
+	}
+	name, _ := lookupRoute(travisTx)
+	return name
+}
+
 func lookupRoute(tx sdk.Tx) (string, error) {
 	kind, err := tx.GetKind()
 	if err != nil {
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CyberMiles/travis/modules/multisig"
+	"github.com/CyberMiles/travis/modules/stake"
+	"github.com/CyberMiles/travis/sdk"
+	"github.com/CyberMiles/travis/utils"
+)
+
+func TestRouteOf(t *testing.T) {
+	assert := assert.New(t)
+	utils.SetParams(utils.DefaultParams())
+
+	key, _ := crypto.GenerateKey()
+	signer := ethTypes.NewEIP155Signer(big.NewInt(19))
+	// signTx sends the tx through the rlp encoding of the abci txs
+	signTx := func(tx *ethTypes.Transaction) *ethTypes.Transaction {
+		signed, err := ethTypes.SignTx(tx, signer, key)
+		assert.Nil(err)
+		var buf bytes.Buffer
+		assert.Nil(signed.EncodeRLP(&buf))
+		decoded, err := decodeTx(buf.Bytes())
+		assert.Nil(err)
+		return decoded
+	}
+	travisTx := func(tx sdk.Tx) *ethTypes.Transaction {
+		data, err := json.Marshal(tx)
+		assert.Nil(err)
+		return signTx(ethTypes.NewContractCreation(0, big.NewInt(0), 1000000, big.NewInt(0), data))
+	}
+
+	alice := common.HexToAddress("0x01")
+	bob := common.HexToAddress("0x02")
+	account := multisig.MultisigAddress(alice, 0)
+	delegate := stake.NewTxDelegate(alice, "1000", "", "", "")
+
+	cases := []struct {
+		tx    *ethTypes.Transaction
+		route string
+	}{
+		{travisTx(multisig.NewTxCreateMultisig([]common.Address{alice, bob}, 2)), "multisig"},
+		{travisTx(multisig.NewTxProposeMultisig(account, delegate)), "multisig"},
+		{travisTx(multisig.NewTxApproveMultisig(account, 1)), "multisig"},
+		{travisTx(multisig.NewTxExecuteMultisig(account, 1)), "multisig"},
+		{travisTx(delegate), "stake"},
+		{signTx(ethTypes.NewTransaction(0, bob, big.NewInt(1), 21000, big.NewInt(0), nil)), ethRoute},
+		{signTx(ethTypes.NewContractCreation(0, big.NewInt(0), 1000000, big.NewInt(0), []byte{0x60, 0x60})), ethRoute},
+	}
+	for _, c := range cases {
+		assert.Equal(c.route, routeOf(c.tx, 1))
+	}
+
+	// before declared_gas_height the travis txs declare no gas
+	params := utils.DefaultParams()
+	params.DeclaredGasHeight = 10
+	utils.SetParams(params)
+	assert.Equal(ethRoute, routeOf(travisTx(delegate), 9))
+	assert.Equal("stake", routeOf(travisTx(delegate), 10))
+	legacy := signTx(ethTypes.NewContractCreation(0, big.NewInt(0), 0, big.NewInt(0), cases[0].tx.Data()))
+	assert.Equal("multisig", routeOf(legacy, 9))
+}
@@ -0,0 +1,209 @@
+package app
+
+import (
+	"math/big"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	abciTypes "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/CyberMiles/travis/errors"
+	"github.com/CyberMiles/travis/metrics"
+	"github.com/CyberMiles/travis/utils"
+)
+
+// lowPriceTx is a low-price tx admitted into the mempool
+type lowPriceTx struct {
+	ft        FromTo
+	gasPrice  *big.Int
+	gas       uint64
+	firstSeen int64
+	lastSeen  int64
+	evicted   bool
+}
+
+// lowPriceTxPolicy decides which low-price txs are admitted into the mempool.
+// Once the txs admitted since the last commit fill a block, the paid txs take
+// the room of the lowest price ones of the lane.
+// It only runs on CheckTx, the DeliverTx keeps using lowPriceTxCheck so that
+// the consensus does not depend on the mempool history of the node.
+type lowPriceTxPolicy struct {
+	mtx        sync.Mutex
+	height     int64
+	gasUsed    uint64 // gas of the txs admitted since the last commit
+	slots      map[FromTo]common.Hash
+	pending    map[common.Hash]*lowPriceTx
+	senders    map[common.Address][]int64
+	rejections map[uint32]uint64
+}
+
+func newLowPriceTxPolicy() *lowPriceTxPolicy {
+	return &lowPriceTxPolicy{
+		slots:      make(map[FromTo]common.Hash),
+		pending:    make(map[common.Hash]*lowPriceTx),
+		senders:    make(map[common.Address][]int64),
+		rejections: make(map[uint32]uint64),
+	}
+}
+
+// LowPriceTxStats holds the number of low-price txs rejected by error code
+type LowPriceTxStats struct {
+	Pending    int               `json:"pending"`
+	Rejections map[uint32]uint64 `json:"rejections"`
+}
+
+func (p *lowPriceTxPolicy) stats() LowPriceTxStats {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	rejections := make(map[uint32]uint64, len(p.rejections))
+	for code, cnt := range p.rejections {
+		rejections[code] = cnt
+	}
+	return LowPriceTxStats{len(p.pending), rejections}
+}
+
+// check admits the tx into the low-price lane, the txs paying the default gas price are always accepted,
+// evicting low-price txs when the block is full. height is the last committed block height, the txs
+// rechecked after each commit are recognized by hash. gasLimit is the gas limit of a block.
+func (p *lowPriceTxPolicy) check(from common.Address, tx *ethTypes.Transaction, height int64, gasLimit uint64) (uint32, string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if height != p.height {
+		p.newHeight(height)
+	}
+
+	params := utils.GetParams()
+	if tx.GasPrice().Cmp(new(big.Int).SetUint64(params.GasPrice)) >= 0 {
+		p.admitPaid(tx.Gas(), tx.GasPrice(), gasLimit)
+		return abciTypes.CodeTypeOK, ""
+	}
+
+	var to common.Address
+	if tx.To() != nil {
+		to = *tx.To()
+	}
+	ft := FromTo{from: from, to: to}
+	hash := tx.Hash()
+
+	ltx, rechecked := p.pending[hash]
+	if rechecked {
+		if ltx.evicted {
+			delete(p.pending, hash)
+			return p.reject(errors.CodeLowPriceTxCapErr, "The low price transaction is evicted by a higher price one")
+		}
+		if params.LowPriceTxMaxAge > 0 && uint64(height-ltx.firstSeen) > params.LowPriceTxMaxAge {
+			delete(p.pending, hash)
+			return p.reject(errors.CodeLowPriceTxCapErr, "The low price transaction is stale")
+		}
+	}
+
+	if h, ok := p.slots[ft]; ok && h != hash {
+		return p.reject(errors.CodeLowGasPriceErr, "The gas price is too low for transaction")
+	}
+	// Bypass if the gasprice == 0 and gaslimit > lowPriceCap
+	if (tx.GasPrice().Int64() > 0 || tx.To() == nil) && tx.Gas() > params.LowPriceTxGasLimit {
+		return p.reject(errors.CodeHighGasLimitErr, "The gas limit is too high for low price transaction")
+	}
+
+	if !rechecked && params.LowPriceTxSenderLimit > 0 && len(p.senders[from]) >= params.LowPriceTxSenderLimit {
+		return p.reject(errors.CodeLowGasPriceErr, "Too many low price transactions from the sender, please retry later")
+	}
+
+	if _, ok := p.slots[ft]; !ok && len(p.slots) > params.LowPriceTxSlotsCap {
+		if !p.evictLowest(tx.GasPrice()) {
+			return p.reject(errors.CodeLowPriceTxCapErr, "The capacity of one block is reached for low price transactions")
+		}
+	}
+	if p.gasUsed+tx.Gas() > gasLimit {
+		return p.reject(errors.CodeLowPriceTxCapErr, "The block is full, the low price transaction has to wait")
+	}
+
+	if !rechecked {
+		ltx = &lowPriceTx{ft: ft, gasPrice: tx.GasPrice(), gas: tx.Gas(), firstSeen: height}
+		p.pending[hash] = ltx
+		p.senders[from] = append(p.senders[from], height)
+	}
+	ltx.lastSeen = height
+	p.slots[ft] = hash
+	p.gasUsed += tx.Gas()
+
+	return abciTypes.CodeTypeOK, ""
+}
+
+// checkPaid admits a tx paying at least the default gas price which is not an ethereum tx, like the travis txs
+func (p *lowPriceTxPolicy) checkPaid(gas uint64, gasPrice *big.Int, height int64, gasLimit uint64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if height != p.height {
+		p.newHeight(height)
+	}
+	p.admitPaid(gas, gasPrice, gasLimit)
+}
+
+// admitPaid evicts the lowest price txs of the lane as long as the block is full
+func (p *lowPriceTxPolicy) admitPaid(gas uint64, gasPrice *big.Int, gasLimit uint64) {
+	for p.gasUsed+gas > gasLimit && p.evictLowest(gasPrice) {
+	}
+	p.gasUsed += gas
+}
+
+// newHeight resets the slots of the lane and drops the txs which were not rechecked
+// during the last height, they have been either included in a block or removed from the mempool
+func (p *lowPriceTxPolicy) newHeight(height int64) {
+	for hash, ltx := range p.pending {
+		if ltx.lastSeen < p.height {
+			delete(p.pending, hash)
+		}
+	}
+
+	window := int64(utils.GetParams().LowPriceTxSenderWindow)
+	for sender, heights := range p.senders {
+		i := sort.Search(len(heights), func(i int) bool { return heights[i] > height-window })
+		if i == len(heights) {
+			delete(p.senders, sender)
+		} else {
+			p.senders[sender] = heights[i:]
+		}
+	}
+
+	p.slots = make(map[FromTo]common.Hash)
+	p.gasUsed = 0
+	p.height = height
+}
+
+// evictLowest gives the slot of the lowest price tx of the lane to a tx with a higher price.
+// The evicted tx is removed from the mempool on the next recheck.
+func (p *lowPriceTxPolicy) evictLowest(gasPrice *big.Int) bool {
+	var lowest *lowPriceTx
+	for _, hash := range p.slots {
+		ltx := p.pending[hash]
+		if ltx == nil || ltx.evicted {
+			continue
+		}
+		if lowest == nil || ltx.gasPrice.Cmp(lowest.gasPrice) < 0 ||
+			(ltx.gasPrice.Cmp(lowest.gasPrice) == 0 && ltx.firstSeen > lowest.firstSeen) {
+			lowest = ltx
+		}
+	}
+
+	if lowest == nil || lowest.gasPrice.Cmp(gasPrice) >= 0 {
+		return false
+	}
+	lowest.evicted = true
+	delete(p.slots, lowest.ft)
+	p.gasUsed -= lowest.gas
+	return true
+}
+
+func (p *lowPriceTxPolicy) reject(code uint32, msg string) (uint32, string) {
+	p.rejections[code]++
+	if metrics.Enabled() {
+		metrics.LowPriceTxRejections.WithLabelValues(strconv.FormatUint(uint64(code), 10)).Inc()
+	}
+	return code, msg
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	abciTypes "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/CyberMiles/travis/errors"
+	"github.com/CyberMiles/travis/utils"
+)
+
+func TestLowPriceTxPolicyBlockFull(t *testing.T) {
+	assert := assert.New(t)
+	utils.SetParams(utils.DefaultParams())
+	gasPrice := new(big.Int).SetUint64(utils.GetParams().GasPrice)
+
+	p := newLowPriceTxPolicy()
+	to := common.HexToAddress("0x1")
+	low1 := ethTypes.NewTransaction(0, to, big.NewInt(0), 50000, big.NewInt(1), nil)
+	low2 := ethTypes.NewTransaction(0, to, big.NewInt(0), 50000, big.NewInt(2), nil)
+	low3 := ethTypes.NewTransaction(0, to, big.NewInt(0), 50000, big.NewInt(3), nil)
+	paid := ethTypes.NewTransaction(0, to, big.NewInt(0), 30000, gasPrice, nil)
+
+	code, _ := p.check(common.HexToAddress("0xa1"), low1, 1, 100000)
+	assert.Equal(abciTypes.CodeTypeOK, code)
+	code, _ = p.check(common.HexToAddress("0xa2"), low2, 1, 100000)
+	assert.Equal(abciTypes.CodeTypeOK, code)
+
+	// the block is full for the low-price lane
+	code, _ = p.check(common.HexToAddress("0xa3"), low3, 1, 100000)
+	assert.Equal(errors.CodeLowPriceTxCapErr, code)
+
+	// a paid tx takes the room of the lowest price tx
+	code, _ = p.check(common.HexToAddress("0xa4"), paid, 1, 100000)
+	assert.Equal(abciTypes.CodeTypeOK, code)
+
+	// the evicted tx is removed on the recheck
+	code, _ = p.check(common.HexToAddress("0xa1"), low1, 2, 100000)
+	assert.Equal(errors.CodeLowPriceTxCapErr, code)
+	code, _ = p.check(common.HexToAddress("0xa2"), low2, 2, 100000)
+	assert.Equal(abciTypes.CodeTypeOK, code)
+}
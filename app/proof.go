@@ -0,0 +1,98 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tendermint/iavl"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/CyberMiles/travis/modules/governance"
+	"github.com/CyberMiles/travis/modules/stake"
+	"github.com/CyberMiles/travis/sdk/errors"
+	"github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// provable reports whether the query can be answered with a state proof
+func provable(path string) bool {
+	switch path {
+	case "/validator", "/validators", "/delegator", "/governance/proposal", "/governance/proposals", "/awardInfo":
+		return true
+	}
+	return false
+}
+
+// queryWithProof answers from the rows mirrored in the iavl store, along with
+// the proof of the value against the app hash of the block at the height
+func (app *BaseApp) queryWithProof(reqQuery abci.RequestQuery) (resQuery abci.ResponseQuery) {
+	tree := app.state.Committed()
+
+	height := reqQuery.Height
+	if height == 0 {
+		height = app.CommittedHeight()
+	}
+	resQuery.Height = height
+
+	_, dbHash := tree.GetVersioned(utils.DbHashKey, height)
+	if dbHash == nil {
+		resQuery.Code = errors.CodeTypeBaseInvalidInput
+		resQuery.Log = fmt.Sprintf("state proofs are not enabled at height %d", height)
+		return
+	}
+	block := app.ethereum.BlockChain().GetBlockByNumber(uint64(height))
+	if block == nil {
+		resQuery.Code = errors.CodeTypeBaseInvalidInput
+		resQuery.Log = fmt.Sprintf("no vm block at height %d", height)
+		return
+	}
+	proof := state.StateProof{Height: height, VMHash: block.Hash().Bytes(), DbHash: dbHash}
+
+	var err error
+	switch reqQuery.Path {
+	case "/validator":
+		resQuery.Key = stake.CandidateKey(common.HexToAddress(string(reqQuery.Data)))
+		resQuery.Value, proof.Proof, err = tree.GetVersionedWithProof(resQuery.Key, height)
+	case "/validators":
+		resQuery.Key = utils.CandidateKey
+		resQuery.Value, proof.Proof, err = getRangeWithProof(tree, resQuery.Key, height)
+	case "/delegator":
+		// all the delegations of the delegator
+		resQuery.Key = stake.DelegatorKeyPrefix(common.HexToAddress(string(reqQuery.Data)))
+		resQuery.Value, proof.Proof, err = getRangeWithProof(tree, resQuery.Key, height)
+	case "/governance/proposal":
+		resQuery.Key = governance.ProposalKey(string(reqQuery.Data))
+		resQuery.Value, proof.Proof, err = tree.GetVersionedWithProof(resQuery.Key, height)
+	case "/governance/proposals":
+		resQuery.Key = utils.ProposalKey
+		resQuery.Value, proof.Proof, err = getRangeWithProof(tree, resQuery.Key, height)
+	case "/awardInfo":
+		// the value is the one of the store, the client decodes it
+		resQuery.Key = utils.AwardInfosKey
+		resQuery.Value, proof.Proof, err = tree.GetVersionedWithProof(resQuery.Key, height)
+	}
+	if err != nil {
+		resQuery.Code = errors.CodeTypeBaseInvalidInput
+		resQuery.Log = err.Error()
+		return
+	}
+
+	resQuery.Proof = cdc.MustMarshalBinary(proof)
+	return
+}
+
+// getRangeWithProof returns the values of all the keys with the prefix as a
+// json array, along with the range proof of them
+func getRangeWithProof(tree *state.Bonsai, prefix []byte, height int64) ([]byte, *iavl.RangeProof, error) {
+	_, values, proof, err := tree.Tree.GetVersionedRangeWithProof(prefix, state.PrefixEnd(prefix), 0, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	raws := make([]json.RawMessage, len(values))
+	for i, v := range values {
+		raws[i] = v
+	}
+	b, err := json.Marshal(raws)
+	return b, proof, err
+}
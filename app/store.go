@@ -8,6 +8,7 @@ import (
 	"math/big"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/CyberMiles/travis/utils"
@@ -23,6 +24,8 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/CyberMiles/travis/modules/governance"
+	"github.com/CyberMiles/travis/modules/multisig"
+	"github.com/CyberMiles/travis/modules/sponsor"
 	"github.com/CyberMiles/travis/modules/stake"
 	"github.com/CyberMiles/travis/sdk/dbm"
 	"github.com/CyberMiles/travis/sdk/errors"
@@ -204,20 +207,89 @@ func (app *StoreApp) Query(reqQuery abci.RequestQuery) (resQuery abci.ResponseQu
 	case "/delegator":
 		address := common.HexToAddress(string(reqQuery.Data))
 		delegations := stake.QueryDelegationsByAddress(address)
-		for _, d := range delegations {
-			validator := stake.QueryCandidateById(d.CandidateId)
-			if validator != nil {
-				d.ValidatorAddress = validator.OwnerAddress
-				d.PubKey = validator.PubKey
-			}
-		}
+		fillValidators(delegations)
 
 		b, _ := json.Marshal(delegations)
 		resQuery.Value = b
+	case "/validators/page":
+		var filter stake.CandidateFilter
+		if err := json.Unmarshal(reqQuery.Data, &filter); err != nil {
+			resQuery.Code = errors.CodeTypeEncodingErr
+			resQuery.Log = err.Error()
+			break
+		}
+		b, _ := json.Marshal(stake.QueryCandidatesPage(filter))
+		resQuery.Value = b
+	case "/delegations/page":
+		var filter stake.DelegationFilter
+		if err := json.Unmarshal(reqQuery.Data, &filter); err != nil {
+			resQuery.Code = errors.CodeTypeEncodingErr
+			resQuery.Log = err.Error()
+			break
+		}
+		page := stake.QueryDelegationsPage(filter)
+		fillValidators(page.Delegations)
+
+		b, _ := json.Marshal(page)
+		resQuery.Value = b
+	case "/validator/delegations":
+		var filter stake.ValidatorDelegationsFilter
+		if err := json.Unmarshal(reqQuery.Data, &filter); err != nil {
+			resQuery.Code = errors.CodeTypeEncodingErr
+			resQuery.Log = err.Error()
+			break
+		}
+		page := stake.QueryValidatorDelegationsPage(filter)
+		if page != nil {
+			fillValidators(page.Delegations)
+			b, _ := json.Marshal(page)
+			resQuery.Value = b
+		} else {
+			resQuery.Value = []byte{}
+		}
+	case "/validatorSetChanges":
+		h, _ := strconv.ParseInt(string(reqQuery.Data), 10, 64)
+		changes := stake.QueryValidatorSetChanges(h)
+		b, _ := json.Marshal(changes)
+		resQuery.Value = b
+	case "/sponsorship":
+		contract := common.HexToAddress(string(reqQuery.Data))
+		sponsorship := sponsor.GetSponsorship(tree, contract)
+		if sponsorship != nil {
+			b, _ := json.Marshal(sponsorship)
+			resQuery.Value = b
+		} else {
+			resQuery.Value = []byte{}
+		}
+	case "/multisig":
+		address := common.HexToAddress(string(reqQuery.Data))
+		account := multisig.GetMultisig(tree, address)
+		if account != nil {
+			b, _ := json.Marshal(multisig.MultisigInfo{Multisig: account, Txs: multisig.GetMultisigTxs(tree, address)})
+			resQuery.Value = b
+		} else {
+			resQuery.Value = []byte{}
+		}
 	case "/governance/proposals":
 		proposals := governance.QueryProposals()
 		b, _ := json.Marshal(proposals)
 		resQuery.Value = b
+	case "/governance/proposal":
+		proposal := governance.GetProposalById(string(reqQuery.Data))
+		if proposal != nil {
+			b, _ := json.Marshal(proposal)
+			resQuery.Value = b
+		} else {
+			resQuery.Value = []byte{}
+		}
+	case "/governance/upgradeStatus":
+		status := governance.QueryUpgradeStatus(string(reqQuery.Data))
+		if status != nil {
+			b, _ := json.Marshal(status)
+			resQuery.Value = b
+		} else {
+			resQuery.Value = []byte{}
+		}
 	case "/awardInfo":
 		_, value := tree.GetVersioned(utils.AwardInfosKey, height)
 		var awardInfos stake.AwardInfos
@@ -338,9 +410,21 @@ func (app *StoreApp) GetDbHash() []byte {
 	for _, table := range tables {
 		hashes = append(hashes, getTableHash(db, table)...)
 	}
+	// hashed only once a validator signaled it's ready to upgrade, so the hash of the former blocks is kept
+	if !isTableEmpty(db, "governance_upgrade_ready") {
+		hashes = append(hashes, getTableHash(db, "governance_upgrade_ready")...)
+	}
 	return hashing(hashes)
 }
 
+func isTableEmpty(db *sql.DB, table string) bool {
+	var cnt int64
+	if err := db.QueryRow("select count(*) from " + table).Scan(&cnt); err != nil {
+		panic(err)
+	}
+	return cnt == 0
+}
+
 func getTableHash(db *sql.DB, table string) []byte {
 	stmt, err := db.Prepare("select hash from " + table + " where 1=1 order by hash")
 	if err != nil {
@@ -376,3 +460,14 @@ func hashing(h []byte) []byte {
 	hasher.Write(buf.Bytes())
 	return hasher.Sum(nil)
 }
+
+// fillValidators sets the address and the pubkey of the validators of the delegations
+func fillValidators(delegations []*stake.Delegation) {
+	for _, d := range delegations {
+		validator := stake.QueryCandidateById(d.CandidateId)
+		if validator != nil {
+			d.ValidatorAddress = validator.OwnerAddress
+			d.PubKey = validator.PubKey
+		}
+	}
+}
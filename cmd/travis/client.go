@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/spf13/cobra"
 
+	govcmd "github.com/CyberMiles/travis/modules/governance/commands"
 	stakecmd "github.com/CyberMiles/travis/modules/stake/commands"
 	"github.com/CyberMiles/travis/sdk/client/commands"
 	"github.com/CyberMiles/travis/sdk/client/commands/query"
@@ -25,7 +26,11 @@ func prepareClientCommands() {
 		stakecmd.CmdQueryValidator,
 		stakecmd.CmdQueryValidators,
 		stakecmd.CmdQueryDelegator,
+		stakecmd.CmdQueryDelegations,
 		stakecmd.CmdQueryAwardInfo,
+		stakecmd.CmdQueryValidatorSetChanges,
+		govcmd.CmdQueryProposals,
+		govcmd.CmdQueryProposal,
 	)
 
 	// set up the middleware
@@ -48,6 +53,8 @@ func prepareClientCommands() {
 
 	clientCmd.AddCommand(
 		txcmd.RootCmd,
+		txcmd.SignCmd,
+		txcmd.BroadcastCmd,
 		query.RootCmd,
 		lineBreak,
 
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"path/filepath"
 	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -12,41 +14,117 @@ import (
 	"github.com/CyberMiles/travis/console"
 )
 
+// nolint
+const (
+	FlagExec    = "exec"
+	FlagPreload = "preload"
+	FlagURL     = "url"
+)
+
 var (
 	attachCmd = &cobra.Command{
 		RunE:  remoteConsole,
 		Use:   "attach",
 		Short: "Start an interactive JavaScript environment (connect to node)",
 	}
+
+	jsCmd = &cobra.Command{
+		RunE:  ephemeralConsole,
+		Use:   "js <file.js>...",
+		Short: "Execute the JavaScript files (connect to node)",
+	}
 )
 
+func init() {
+	attachCmd.Flags().String(FlagExec, "", "Execute the JavaScript statement and exit, instead of starting the interactive mode")
+	attachCmd.Flags().String(FlagPreload, "", "Comma separated list of JavaScript files to preload into the console")
+
+	jsCmd.Flags().String(FlagURL, "http://localhost:8545", "Url of the node to connect to")
+	jsCmd.Flags().String(FlagPreload, "", "Comma separated list of JavaScript files to preload before the executed ones")
+}
+
 func remoteConsole(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 || len(args[0]) == 0 {
 		return errors.New("missing url")
 	}
 
-	client, err := dialRPC(args[0])
+	console, err := newConsole(args[0])
+	if err != nil {
+		return err
+	}
+	defer console.Stop(false)
+
+	// If only a short execution was requested, evaluate and return
+	if script := viper.GetString(FlagExec); script != "" {
+		return console.Evaluate(script)
+	}
+
+	// Otherwise print the welcome screen and enter interactive mode
+	console.Welcome()
+	console.Interactive()
+
+	return nil
+}
+
+// ephemeralConsole runs the JavaScript files one after the other and stops at
+// the first failing one
+func ephemeralConsole(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing JavaScript file")
+	}
+
+	console, err := newConsole(viper.GetString(FlagURL))
+	if err != nil {
+		return err
+	}
+	// wait for the callbacks of the scripts
+	defer console.Stop(true)
+
+	for _, file := range args {
+		if err = console.Execute(file); err != nil {
+			return errors.Errorf("Failed to execute %s: %v", file, err)
+		}
+	}
+	return nil
+}
+
+func newConsole(endpoint string) (*console.Console, error) {
+	client, err := dialRPC(endpoint)
 	if err != nil {
 		utils.Fatalf("Unable to attach to remote node: %v", err)
 	}
+	preload, err := preloads()
+	if err != nil {
+		return nil, err
+	}
 	config := console.Config{
 		DataDir: "",
 		DocRoot: "",
 		Client:  client,
-		Preload: []string{},
+		Preload: preload,
 	}
 
 	console, err := console.New(config)
 	if err != nil {
 		utils.Fatalf("Failed to start the JavaScript console: %v", err)
 	}
-	defer console.Stop(false)
-
-	// Otherwise print the welcome screen and enter interactive mode
-	console.Welcome()
-	console.Interactive()
+	return console, nil
+}
 
-	return nil
+// preloads returns the absolute paths of the --preload files
+func preloads() ([]string, error) {
+	preload := []string{}
+	for _, file := range strings.Split(viper.GetString(FlagPreload), ",") {
+		if file = strings.TrimSpace(file); file == "" {
+			continue
+		}
+		path, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		preload = append(preload, path)
+	}
+	return preload, nil
 }
 
 func dialRPC(endpoint string) (*rpc.Client, error) {
@@ -38,6 +38,7 @@ func main() {
 		nodeCmd,
 		clientCmd,
 		attachCmd,
+		jsCmd,
 		basecmd.RemoveAddrBookCmd,
 		basecmd.ResetPrivValidatorCmd,
 		versionCmd,
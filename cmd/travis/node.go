@@ -17,5 +17,9 @@ func prepareNodeCommands() {
 		basecmd.InitCmd,
 		basecmd.GetStartCmd(),
 		basecmd.ShowNodeIDCmd,
+		basecmd.DbCmd,
+		basecmd.ExportCmd,
+		basecmd.ValidateGenesisCmd,
+		basecmd.TestnetCmd,
 	)
 }
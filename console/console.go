@@ -136,6 +136,18 @@ func (c *Console) init(preload []string) error {
 		if api == "web3" {
 			continue // manually mapped or ignore
 		}
+		if api == "cmt" {
+			// Load the stake and the governance modules of the cmt api.
+			if err = c.jsre.Compile("cmt-formatters.js", web3ext.Cmt_Formatters_JS); err != nil {
+				return fmt.Errorf("cmt-formatters.js: %v", err)
+			}
+			for module, file := range web3ext.CmtModules {
+				if err = c.jsre.Compile(fmt.Sprintf("%s.js", module), file); err != nil {
+					return fmt.Errorf("%s.js: %v", module, err)
+				}
+				flatten += fmt.Sprintf("var %s = web3.%s; ", module, module)
+			}
+		}
 		if file, ok := web3ext.Modules[api]; ok {
 			// Load our extension for the module.
 			if err = c.jsre.Compile(fmt.Sprintf("%s.js", api), file); err != nil {
@@ -272,11 +284,12 @@ func (c *Console) Welcome() {
 }
 
 // Evaluate executes code and pretty prints the result to the specified output
-// stream.
-func (c *Console) Evaluate(statement string) error {
+// stream. The exception of the code, if any, is returned too.
+func (c *Console) Evaluate(statement string) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Fprintf(c.printer, "[native] error: %v\n", r)
+			err = fmt.Errorf("%v", r)
 		}
 	}()
 	return c.jsre.Evaluate(statement, c.printer)
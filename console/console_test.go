@@ -185,7 +185,9 @@ func TestEvaluate(t *testing.T) {
 	tester := newTester(t, nil)
 	defer tester.Close(t)
 
-	tester.console.Evaluate("2 + 2")
+	if err := tester.console.Evaluate("2 + 2"); err != nil {
+		t.Fatalf("statement evaluation failed: %v", err)
+	}
 	if output := string(tester.output.Bytes()); !strings.Contains(output, "4") {
 		t.Fatalf("statement evaluation failed: have %s, want %s", output, "4")
 	}
@@ -282,7 +284,9 @@ func TestPrettyPrint(t *testing.T) {
 func TestPrettyError(t *testing.T) {
 	tester := newTester(t, nil)
 	defer tester.Close(t)
-	tester.console.Evaluate("throw 'hello'")
+	if err := tester.console.Evaluate("throw 'hello'"); err == nil {
+		t.Fatalf("exception not returned")
+	}
 
 	want := jsre.ErrorColor("hello") + "\n"
 	if output := string(tester.output.Bytes()); output != want {
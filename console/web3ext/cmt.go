@@ -0,0 +1,417 @@
+package web3ext
+
+// CmtModules are the console modules of the stake and the governance, they are
+// loaded when the node serves the cmt api.
+var CmtModules = map[string]string{
+	"stake":      Stake_JS,
+	"governance": Governance_JS,
+}
+
+// Cmt_Formatters_JS is loaded before the cmt modules. The amounts of the txs
+// are given with their unit, as "1.5cmt" or "500wei", a number is taken in wei,
+// and the amounts of the candidates and the delegations are shown in cmt.
+// The queries of a height can be called without it for the last committed height.
+const Cmt_Formatters_JS = `
+var cmtFormatters = {
+	inputTx: function(args) {
+		args.from = web3._extend.formatters.inputAddressFormatter(args.from);
+		['nonce', 'creationNonce', 'threshold', 'id'].forEach(function(name) {
+			if (args[name] !== undefined && args[name] !== null) {
+				args[name] = web3._extend.utils.fromDecimal(args[name]);
+			}
+		});
+		['amount', 'maxAmount', 'maxFeePerUser', 'maxFeePerBlock'].forEach(function(name) {
+			if (args[name] !== undefined && args[name] !== null) {
+				args[name] = cmtFormatters.inputAmount(args[name]);
+			}
+		});
+		return args;
+	},
+	// inputAmount turns "1.5cmt" or "500wei" into hex wei
+	inputAmount: function(amount) {
+		if (web3._extend.utils.isString(amount)) {
+			var s = amount.trim().toLowerCase();
+			if (/^0x[0-9a-f]+$/.test(s)) {
+				return s;
+			}
+			var m = s.match(/^([0-9]+(\.[0-9]+)?)\s*(cmt|wei)$/);
+			if (!m) {
+				throw new Error('invalid amount ' + amount + ', use 1.5cmt or 500wei');
+			}
+			amount = m[3] === 'cmt' ? web3._extend.utils.toWei(m[1], 'ether') : m[1];
+		}
+		var wei = web3._extend.utils.toBigNumber(amount);
+		if (!wei.isInteger() || wei.isNegative()) {
+			throw new Error('invalid amount ' + amount + ', it is not a whole number of wei');
+		}
+		return web3._extend.utils.toHex(wei);
+	},
+	inputHeight: function(height) {
+		return height === undefined || height === null ? 0 : web3._extend.utils.toDecimal(height);
+	},
+	inputPage: function(page) {
+		return page ? page : null;
+	},
+
+	outputAmount: function(wei) {
+		if (wei === undefined || wei === null || wei === '') {
+			return wei;
+		}
+		return web3._extend.utils.fromWei(wei, 'ether') + 'cmt';
+	},
+	// outputRate shows a rate like "1/5" as 0.2
+	outputRate: function(rate) {
+		var parts = web3._extend.utils.isString(rate) ? rate.split('/') : [];
+		if (parts.length !== 2) {
+			return rate;
+		}
+		return web3._extend.utils.toBigNumber(parts[0]).div(parts[1]).toString(10);
+	},
+	outputCandidate: function(c) {
+		if (!c) {
+			return c;
+		}
+		c.shares = cmtFormatters.outputAmount(c.shares);
+		c.max_shares = cmtFormatters.outputAmount(c.max_shares);
+		c.comp_rate = cmtFormatters.outputRate(c.comp_rate);
+		return c;
+	},
+	outputDelegation: function(d) {
+		if (!d) {
+			return d;
+		}
+		['delegate_amount', 'award_amount', 'withdraw_amount', 'pending_withdraw_amount', 'slash_amount'].forEach(function(name) {
+			d[name] = cmtFormatters.outputAmount(d[name]);
+		});
+		d.comp_rate = cmtFormatters.outputRate(d.comp_rate);
+		(d.pending_unstake_requests || []).forEach(function(r) {
+			r.amount = cmtFormatters.outputAmount(r.amount);
+		});
+		return d;
+	},
+	outputCandidates: function(candidates) {
+		return candidates ? candidates.map(cmtFormatters.outputCandidate) : candidates;
+	},
+	outputDelegations: function(delegations) {
+		return delegations ? delegations.map(cmtFormatters.outputDelegation) : delegations;
+	},
+	// outputResult formats the data of the query result
+	outputResult: function(format) {
+		return function(res) {
+			if (res) {
+				res.data = format(res.data);
+			}
+			return res;
+		};
+	},
+	outputPage: function(page) {
+		if (page && page.validator) {
+			cmtFormatters.outputCandidate(page.validator);
+		}
+		if (page && page.candidates) {
+			cmtFormatters.outputCandidates(page.candidates);
+		}
+		if (page && page.delegations) {
+			cmtFormatters.outputDelegations(page.delegations);
+		}
+		return page;
+	},
+
+	// optionalHeight lets the height of the query be omitted
+	optionalHeight: function(obj, name, params) {
+		var method = obj[name];
+		obj[name] = function() {
+			var args = Array.prototype.slice.call(arguments);
+			var callback = web3._extend.utils.isFunction(args[args.length - 1]) ? args.pop() : null;
+			while (args.length < params) {
+				args.push(0);
+			}
+			if (callback) {
+				args.push(callback);
+			}
+			return method.apply(obj, args);
+		};
+		obj[name].call = method.call;
+		obj[name].request = method.request;
+	}
+};
+`
+
+const Stake_JS = `
+web3._extend({
+	property: 'stake',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'declareCandidacy',
+			call: 'cmt_declareCandidacy',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'updateCandidacy',
+			call: 'cmt_updateCandidacy',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'withdrawCandidacy',
+			call: 'cmt_withdrawCandidacy',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'verifyCandidacy',
+			call: 'cmt_verifyCandidacy',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'activateCandidacy',
+			call: 'cmt_activateCandidacy',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'deactivateCandidacy',
+			call: 'cmt_deactivateCandidacy',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'setCompRate',
+			call: 'cmt_setCompRate',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'updateCandidacyAccount',
+			call: 'cmt_updateCandidacyAccount',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'acceptCandidacyAccountUpdate',
+			call: 'cmt_acceptCandidacyAccountUpdate',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'delegate',
+			call: 'cmt_delegate',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'withdraw',
+			call: 'cmt_withdraw',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'openSponsorship',
+			call: 'cmt_openSponsorship',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'fundSponsorship',
+			call: 'cmt_fundSponsorship',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'withdrawSponsorship',
+			call: 'cmt_withdrawSponsorship',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'setSponsorshipLimits',
+			call: 'cmt_setSponsorshipLimits',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'queryValidators',
+			call: 'cmt_queryValidators',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputHeight],
+			outputFormatter: cmtFormatters.outputResult(cmtFormatters.outputCandidates)
+		}),
+		new web3._extend.Method({
+			name: 'queryValidator',
+			call: 'cmt_queryValidator',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, cmtFormatters.inputHeight],
+			outputFormatter: cmtFormatters.outputResult(cmtFormatters.outputCandidate)
+		}),
+		new web3._extend.Method({
+			name: 'queryDelegator',
+			call: 'cmt_queryDelegator',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, cmtFormatters.inputHeight],
+			outputFormatter: cmtFormatters.outputResult(cmtFormatters.outputDelegations)
+		}),
+		new web3._extend.Method({
+			name: 'queryValidatorsPage',
+			call: 'cmt_queryValidatorsPage',
+			params: 2,
+			inputFormatter: [null, cmtFormatters.inputHeight],
+			outputFormatter: cmtFormatters.outputResult(cmtFormatters.outputPage)
+		}),
+		new web3._extend.Method({
+			name: 'queryDelegationsPage',
+			call: 'cmt_queryDelegationsPage',
+			params: 2,
+			inputFormatter: [null, cmtFormatters.inputHeight],
+			outputFormatter: cmtFormatters.outputResult(cmtFormatters.outputPage)
+		}),
+		new web3._extend.Method({
+			name: 'queryDelegations',
+			call: 'cmt_queryDelegations',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, cmtFormatters.inputHeight, cmtFormatters.inputPage],
+			outputFormatter: cmtFormatters.outputResult(cmtFormatters.outputPage)
+		}),
+		new web3._extend.Method({
+			name: 'querySponsorship',
+			call: 'cmt_querySponsorship',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, cmtFormatters.inputHeight]
+		}),
+		new web3._extend.Method({
+			name: 'queryAwardInfos',
+			call: 'cmt_queryAwardInfos',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputHeight]
+		}),
+		new web3._extend.Method({
+			name: 'queryAbsentValidators',
+			call: 'cmt_queryAbsentValidators',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputHeight]
+		}),
+		new web3._extend.Method({
+			name: 'queryValidatorSetChanges',
+			call: 'cmt_queryValidatorSetChanges',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputHeight]
+		}),
+		new web3._extend.Method({
+			name: 'queryLowPriceTxStats',
+			call: 'cmt_queryLowPriceTxStats',
+			params: 0
+		})
+	]
+});
+cmtFormatters.optionalHeight(web3.stake, 'queryValidators', 1);
+cmtFormatters.optionalHeight(web3.stake, 'queryValidator', 2);
+cmtFormatters.optionalHeight(web3.stake, 'queryDelegator', 2);
+cmtFormatters.optionalHeight(web3.stake, 'queryValidatorsPage', 2);
+cmtFormatters.optionalHeight(web3.stake, 'queryDelegationsPage', 2);
+cmtFormatters.optionalHeight(web3.stake, 'queryDelegations', 3);
+cmtFormatters.optionalHeight(web3.stake, 'querySponsorship', 2);
+cmtFormatters.optionalHeight(web3.stake, 'queryAwardInfos', 1);
+cmtFormatters.optionalHeight(web3.stake, 'queryAbsentValidators', 1);
+cmtFormatters.optionalHeight(web3.stake, 'queryValidatorSetChanges', 1);
+`
+
+const Governance_JS = `
+web3._extend({
+	property: 'governance',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'proposeTransferFund',
+			call: 'cmt_proposeTransferFund',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'proposeChangeParam',
+			call: 'cmt_proposeChangeParam',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'proposeDeployLibEni',
+			call: 'cmt_proposeDeployLibEni',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'proposeRetireProgram',
+			call: 'cmt_proposeRetireProgram',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'proposeUpgradeProgram',
+			call: 'cmt_proposeUpgradeProgram',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'vote',
+			call: 'cmt_vote',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'signalUpgradeReady',
+			call: 'cmt_signalUpgradeReady',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'createMultisig',
+			call: 'cmt_createMultisig',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'proposeMultisig',
+			call: 'cmt_proposeMultisig',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'approveMultisig',
+			call: 'cmt_approveMultisig',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'executeMultisig',
+			call: 'cmt_executeMultisig',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputTx]
+		}),
+		new web3._extend.Method({
+			name: 'queryProposals',
+			call: 'cmt_queryProposals',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'queryUpgradeStatus',
+			call: 'cmt_queryUpgradeStatus',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'queryParams',
+			call: 'cmt_queryParams',
+			params: 1,
+			inputFormatter: [cmtFormatters.inputHeight]
+		}),
+		new web3._extend.Method({
+			name: 'queryMultisig',
+			call: 'cmt_queryMultisig',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, cmtFormatters.inputHeight]
+		})
+	]
+});
+cmtFormatters.optionalHeight(web3.governance, 'queryParams', 1);
+cmtFormatters.optionalHeight(web3.governance, 'queryMultisig', 2);
+`
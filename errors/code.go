@@ -15,4 +15,5 @@ const (
 	CodeLowGasPriceErr        uint32 = 101
 	CodeHighGasLimitErr       uint32 = 102
 	CodeLowPriceTxCapErr      uint32 = 103
+	CodeLowGasLimitErr        uint32 = 104
 )
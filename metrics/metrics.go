@@ -0,0 +1,109 @@
+// Package metrics holds the prometheus metrics of the staking, the awards and
+// the governance of travis, they are collected only if enabled in the config
+package metrics
+
+import (
+	"math/big"
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "travis"
+
+var (
+	enabled = false
+
+	ValidatorVotingPower = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "stake",
+		Name:      "voting_power",
+		Help:      "Voting power of the active candidates.",
+	}, []string{"address"})
+	ValidatorState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "stake",
+		Name:      "candidate_state",
+		Help:      "State of the active candidates, 1 for the current one.",
+	}, []string{"address", "state"})
+	AbsentBlocks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "stake",
+		Name:      "absent_blocks_total",
+		Help:      "Blocks the validators did not sign.",
+	}, []string{"pub_key"})
+	Slashes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "stake",
+		Name:      "slashes_total",
+		Help:      "Slashes of the validators by reason.",
+	}, []string{"reason"})
+	PendingUnstake = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "stake",
+		Name:      "pending_unstake_cmt",
+		Help:      "CMTs of the pending unstake requests.",
+	})
+	BlockAwardMinted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "award",
+		Name:      "minted_cmt_total",
+		Help:      "CMTs minted as the block awards.",
+	})
+	FeesDistributed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "award",
+		Name:      "fees_distributed_cmt_total",
+		Help:      "CMTs of the transaction fees distributed with the block awards.",
+	})
+	LowPriceTxRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mempool",
+		Name:      "low_price_tx_rejections_total",
+		Help:      "Low-price transactions rejected by error code.",
+	}, []string{"code"})
+	Proposals = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "governance",
+		Name:      "proposals",
+		Help:      "Proposals by status.",
+	}, []string{"status"})
+	SqlCommitSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "app",
+		Name:      "sql_commit_seconds",
+		Help:      "Time the sqlite transaction of a block takes to commit.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 12),
+	})
+)
+
+func init() {
+	prometheus.MustRegister(ValidatorVotingPower, ValidatorState, AbsentBlocks, Slashes, PendingUnstake,
+		BlockAwardMinted, FeesDistributed, LowPriceTxRejections, Proposals, SqlCommitSeconds)
+}
+
+// Enabled tells whether the metrics are collected
+func Enabled() bool {
+	return enabled
+}
+
+// Start enables the metrics and serves them, with the ones of tendermint, at /metrics on the address
+func Start(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	enabled = true
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go http.Serve(l, mux)
+	return nil
+}
+
+// ToCmt converts wei to CMT for the metrics
+func ToCmt(wei *big.Int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18)).Float64()
+	return f
+}
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/CyberMiles/travis/modules/governance"
+	"github.com/CyberMiles/travis/sdk/client/commands/query"
+	"github.com/CyberMiles/travis/utils"
+)
+
+// nolint
+const (
+	FlagId = "id"
+)
+
+// nolint
+var (
+	CmdQueryProposals = &cobra.Command{
+		Use:   "proposals",
+		RunE:  cmdQueryProposals,
+		Short: "Query all the governance proposals",
+	}
+
+	CmdQueryProposal = &cobra.Command{
+		Use:   "proposal",
+		RunE:  cmdQueryProposal,
+		Short: "Query a governance proposal",
+	}
+)
+
+func init() {
+	CmdQueryProposal.Flags().String(FlagId, "", "proposal id")
+}
+
+func cmdQueryProposals(cmd *cobra.Command, args []string) error {
+	b, err := query.Get("/governance/proposals", []byte{0}, query.VerifyRange(utils.ProposalKey))
+	if err != nil {
+		return err
+	}
+	return output(b)
+}
+
+func cmdQueryProposal(cmd *cobra.Command, args []string) error {
+	id := viper.GetString(FlagId)
+	if id == "" {
+		return fmt.Errorf("please enter proposal id using --id")
+	}
+
+	b, err := query.Get("/governance/proposal", []byte(id), query.VerifyKey(governance.ProposalKey(id)))
+	if err != nil {
+		return err
+	}
+	return output(b)
+}
+
+func output(b []byte) error {
+	_, err := fmt.Fprintf(os.Stdout, "%s\n", b)
+	return err
+}
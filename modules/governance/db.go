@@ -6,6 +6,7 @@ import (
 
 	"database/sql"
 	"github.com/CyberMiles/travis/sdk/dbm"
+	"github.com/CyberMiles/travis/types"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -70,6 +71,7 @@ func (wrapper *SqlTxWrapper) Rollback() {
 func SaveProposal(pp *Proposal) {
 	txWrapper := getSqlTxWrapper()
 	defer txWrapper.Commit()
+	proposalMirror.Touch(ProposalKey(pp.Id))
 
 	stmt, err := txWrapper.tx.Prepare("insert into governance_proposal(id, type, proposer, block_height, expire_timestamp, expire_block_height, hash) values(?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
@@ -349,6 +351,7 @@ func UpdateProposalResult(pid, result, msg string, blockHeight int64) {
 
 	txWrapper := getSqlTxWrapper()
 	defer txWrapper.Commit()
+	proposalMirror.Touch(ProposalKey(pid))
 
 	stmt, err := txWrapper.tx.Prepare("update governance_proposal set result = ?, result_msg = ?, result_block_height = ?, hash = ? where id = ?")
 	if err != nil {
@@ -365,6 +368,17 @@ func UpdateProposalResult(pid, result, msg string, blockHeight int64) {
 		fmt.Println(err)
 		panic(err)
 	}
+
+	var addrs []common.Address
+	if p.Proposer != nil {
+		addrs = append(addrs, *p.Proposer)
+	}
+	types.EventBus.Publish(types.EventProposalDecided, blockHeight, addrs, map[string]interface{}{
+		"proposalId": p.Id,
+		"type":       p.Type,
+		"result":     result,
+		"resultMsg":  msg,
+	})
 }
 
 func UpdateDeployLibEniStatus(pid, status string) {
@@ -393,6 +407,7 @@ func UpdateDeployLibEniStatus(pid, status string) {
 func UpdateRetireProgramStatus(pid, status string) {
 	txWrapper := getSqlTxWrapper()
 	defer txWrapper.Commit()
+	proposalMirror.Touch(ProposalKey(pid))
 
 	stmt, err := txWrapper.tx.Prepare("update governance_retire_program_detail set status = ? where proposal_id = ?")
 	if err != nil {
@@ -603,6 +618,34 @@ func GetPendingProposals() (proposals []*Proposal) {
 	return
 }
 
+// CountProposalsByResult returns the number of proposals by result, the undecided ones are counted under ""
+func CountProposalsByResult() map[string]int64 {
+	txWrapper := getSqlTxWrapper()
+	defer txWrapper.Commit()
+
+	rows, err := txWrapper.tx.Query("select result, count(*) from governance_proposal group by result")
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var result string
+		var count int64
+		if err = rows.Scan(&result, &count); err != nil {
+			panic(err)
+		}
+		counts[result] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		panic(err)
+	}
+
+	return counts
+}
+
 func GetRetiringProposal(version string) *Proposal {
 	txWrapper := getSqlTxWrapper()
 	defer txWrapper.Commit()
@@ -823,3 +866,60 @@ func GetVotesByPid(pid string) (votes []*Vote) {
 
 	return
 }
+
+func SaveUpgradeReady(r *UpgradeReady) {
+	txWrapper := getSqlTxWrapper()
+	defer txWrapper.Commit()
+
+	stmt, err := txWrapper.tx.Prepare("insert into governance_upgrade_ready(proposal_id, validator, block_height, hash) values(?, ?, ?, ?)")
+	if err != nil {
+		panic(err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(r.ProposalId, r.Validator.String(), r.BlockHeight, common.Bytes2Hex(r.Hash()))
+	if err != nil {
+		fmt.Println(err)
+		panic(err)
+	}
+}
+
+func GetUpgradeReadiesByPid(pid string) []*UpgradeReady {
+	txWrapper := getSqlTxWrapper()
+	defer txWrapper.Commit()
+
+	return getUpgradeReadies(txWrapper.tx, pid)
+}
+
+func QueryUpgradeReadiesByPid(pid string) []*UpgradeReady {
+	tx, err := getDb().Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Commit()
+
+	return getUpgradeReadies(tx, pid)
+}
+
+func getUpgradeReadies(tx *sql.Tx, pid string) (readies []*UpgradeReady) {
+	rows, err := tx.Query("select validator, block_height from governance_upgrade_ready where proposal_id = ? order by block_height, validator", pid)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var validator string
+		var blockHeight int64
+		if err = rows.Scan(&validator, &blockHeight); err != nil {
+			panic(err)
+		}
+		readies = append(readies, &UpgradeReady{pid, common.HexToAddress(validator), blockHeight})
+	}
+
+	if err = rows.Err(); err != nil {
+		panic(err)
+	}
+
+	return
+}
@@ -27,6 +27,8 @@ var (
 	errOngoingLibFound          = fmt.Errorf("One or more onging proposal with the same lib name")
 	errOngoingRetiringFound     = fmt.Errorf("Found unresolved or approved retiring proposal")
 	errExpirationTooClose       = fmt.Errorf("The proposal's expiration block height is too close")
+	errNotUpgradeProposal       = fmt.Errorf("The proposal is not an upgrade program proposal")
+	errRepeatedUpgradeReady     = fmt.Errorf("The validator is already ready to upgrade")
 )
 
 func ErrMissingSignature() error {
@@ -104,3 +106,11 @@ func ErrOngoingRetiringFound() error {
 func ErrExpirationTooClose() error {
 	return errors.WithCode(errExpirationTooClose, errors.CodeTypeBaseInvalidInput)
 }
+
+func ErrNotUpgradeProposal() error {
+	return errors.WithCode(errNotUpgradeProposal, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrRepeatedUpgradeReady() error {
+	return errors.WithCode(errRepeatedUpgradeReady, errors.CodeTypeBaseInvalidInput)
+}
@@ -0,0 +1,77 @@
+package governance
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/CyberMiles/travis/types"
+	"github.com/CyberMiles/travis/utils"
+)
+
+// ExportGenesis adds the undecided proposals and their votes to the genesis, the
+// expiration heights are counted from the exported height
+func ExportGenesis(genDoc *types.GenesisDoc, height int64) {
+	for _, p := range QueryProposals() {
+		if p.Result != "" {
+			continue
+		}
+		expireBlockHeight := p.ExpireBlockHeight
+		if expireBlockHeight > 0 {
+			expireBlockHeight -= height
+			if expireBlockHeight < 1 {
+				expireBlockHeight = 1
+			}
+		}
+		gp := types.GenesisProposal{
+			Id:                p.Id,
+			Type:              p.Type,
+			Proposer:          p.Proposer.String(),
+			ExpireTimestamp:   p.ExpireTimestamp,
+			ExpireBlockHeight: expireBlockHeight,
+			Detail:            p.Detail,
+		}
+		for _, v := range GetVotesByPid(p.Id) {
+			gp.Votes = append(gp.Votes, types.GenesisVote{Voter: v.Voter.String(), Answer: v.Answer})
+		}
+		genDoc.Proposals = append(genDoc.Proposals, gp)
+	}
+}
+
+// ImportGenesis restores the proposals exported from a running chain
+func ImportGenesis(genDoc *types.GenesisDoc) error {
+	for _, gp := range genDoc.Proposals {
+		if GetProposalById(gp.Id) != nil {
+			return fmt.Errorf("the proposal %s is imported twice", gp.Id)
+		}
+		detail := make(map[string]interface{})
+		for k, v := range gp.Detail {
+			detail[k] = v
+		}
+		if gp.Type == TRANSFER_FUND_PROPOSAL {
+			for _, k := range []string{"from", "to"} {
+				s, ok := detail[k].(string)
+				if !ok || !common.IsHexAddress(s) {
+					return fmt.Errorf("the proposal %s has an invalid %s address", gp.Id, k)
+				}
+				addr := common.HexToAddress(s)
+				detail[k] = &addr
+			}
+		}
+
+		proposer := common.HexToAddress(gp.Proposer)
+		SaveProposal(&Proposal{
+			Id:                gp.Id,
+			Type:              gp.Type,
+			Proposer:          &proposer,
+			ExpireTimestamp:   gp.ExpireTimestamp,
+			ExpireBlockHeight: gp.ExpireBlockHeight,
+			Detail:            detail,
+		})
+		for _, gv := range gp.Votes {
+			SaveVote(NewVote(gp.Id, common.HexToAddress(gv.Voter), 0, gv.Answer))
+		}
+		utils.PendingProposal.Add(gp.Id, gp.ExpireTimestamp, gp.ExpireBlockHeight)
+	}
+	return nil
+}
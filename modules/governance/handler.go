@@ -3,6 +3,7 @@ package governance
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 	"strings"
 	"time"
@@ -15,7 +16,6 @@ import (
 	"github.com/CyberMiles/travis/utils"
 	"github.com/CyberMiles/travis/version"
 	"github.com/ethereum/go-ethereum/common"
-	ethState "github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/vm/eni"
 	"net/rpc"
 
@@ -95,7 +95,7 @@ func CheckTx(ctx types.Context, store state.SimpleDB,
 		}
 
 		// Transfer gasFee
-		_, err = checkGasFee(app_state, sender, utils.GetParams().TransferFundProposalGas)
+		_, err = checkGasFee(ctx, sender, utils.GetParams().TransferFundProposalGas)
 		if err != nil {
 			return sdk.NewCheck(0, ""), err
 		}
@@ -133,7 +133,7 @@ func CheckTx(ctx types.Context, store state.SimpleDB,
 		}
 
 		// Transfer gasFee
-		_, err = checkGasFee(app_state, sender, utils.GetParams().ChangeParamsProposalGas)
+		_, err = checkGasFee(ctx, sender, utils.GetParams().ChangeParamsProposalGas)
 		if err != nil {
 			return sdk.NewCheck(0, ""), err
 		}
@@ -201,7 +201,7 @@ func CheckTx(ctx types.Context, store state.SimpleDB,
 		}
 
 		// Transfer gasFee
-		_, err = checkGasFee(app_state, sender, utils.GetParams().DeployLibEniProposalGas)
+		_, err = checkGasFee(ctx, sender, utils.GetParams().DeployLibEniProposalGas)
 		if err != nil {
 			return sdk.NewCheck(0, ""), err
 		}
@@ -234,7 +234,7 @@ func CheckTx(ctx types.Context, store state.SimpleDB,
 		}
 
 		// Transfer gasFee
-		_, err = checkGasFee(app_state, sender, utils.GetParams().RetireProgramProposalGas)
+		_, err = checkGasFee(ctx, sender, utils.GetParams().RetireProgramProposalGas)
 		if err != nil {
 			return sdk.NewCheck(0, ""), err
 		}
@@ -278,7 +278,7 @@ func CheckTx(ctx types.Context, store state.SimpleDB,
 		}
 
 		// Transfer gasFee
-		_, err = checkGasFee(app_state, sender, utils.GetParams().UpgradeProgramProposalGas)
+		_, err = checkGasFee(ctx, sender, utils.GetParams().UpgradeProgramProposalGas)
 		if err != nil {
 			return sdk.NewCheck(0, ""), err
 		}
@@ -313,6 +313,42 @@ func CheckTx(ctx types.Context, store state.SimpleDB,
 				return sdk.NewCheck(0, ""), ErrRejectedProposal()
 			}
 		}
+	case TxUpgradeReady:
+		validators := stake.GetCandidates().Validators()
+		if validators == nil || validators.Len() == 0 {
+			return sdk.NewCheck(0, ""), ErrInvalidValidator()
+		}
+		for i, v := range validators {
+			if v.OwnerAddress == sender.String() {
+				break
+			}
+			if i+1 == len(validators) {
+				return sdk.NewCheck(0, ""), ErrInvalidValidator()
+			}
+		}
+
+		proposal := GetProposalById(txInner.ProposalId)
+		if proposal == nil {
+			return sdk.NewCheck(0, ""), ErrInvalidParameter()
+		}
+
+		if proposal.Type != UPGRADE_PROGRAM_PROPOSAL {
+			return sdk.NewCheck(0, ""), ErrNotUpgradeProposal()
+		}
+
+		if ctx.BlockHeight() >= proposal.ExpireBlockHeight - 2 {
+			return sdk.NewCheck(0, ""), ErrExpirationTooClose()
+		}
+
+		if proposal.Result == "Rejected" {
+			return sdk.NewCheck(0, ""), ErrRejectedProposal()
+		}
+
+		for _, r := range GetUpgradeReadiesByPid(txInner.ProposalId) {
+			if r.Validator == sender {
+				return sdk.NewCheck(0, ""), ErrRepeatedUpgradeReady()
+			}
+		}
 	}
 
 	return
@@ -376,7 +412,7 @@ func DeliverTx(ctx types.Context, store state.SimpleDB,
 		params := utils.GetParams()
 		gasUsed := params.TransferFundProposalGas
 
-		if gasFee, err := checkGasFee(app_state, sender, gasUsed); err != nil {
+		if gasFee, err := checkGasFee(ctx, sender, gasUsed); err != nil {
 			return res, err
 		} else {
 			res.GasFee = gasFee
@@ -422,7 +458,7 @@ func DeliverTx(ctx types.Context, store state.SimpleDB,
 		params := utils.GetParams()
 		gasUsed := params.ChangeParamsProposalGas
 
-		if gasFee, err := checkGasFee(app_state, sender, gasUsed); err != nil {
+		if gasFee, err := checkGasFee(ctx, sender, gasUsed); err != nil {
 			return res, err
 		} else {
 			res.GasFee = gasFee
@@ -471,7 +507,7 @@ func DeliverTx(ctx types.Context, store state.SimpleDB,
 		params := utils.GetParams()
 		gasUsed := params.DeployLibEniProposalGas
 
-		if gasFee, err := checkGasFee(app_state, sender, gasUsed); err != nil {
+		if gasFee, err := checkGasFee(ctx, sender, gasUsed); err != nil {
 			return res, err
 		} else {
 			res.GasFee = gasFee
@@ -514,7 +550,7 @@ func DeliverTx(ctx types.Context, store state.SimpleDB,
 		params := utils.GetParams()
 		gasUsed := params.RetireProgramProposalGas
 
-		if gasFee, err := checkGasFee(app_state, sender, gasUsed); err != nil {
+		if gasFee, err := checkGasFee(ctx, sender, gasUsed); err != nil {
 			return res, err
 		} else {
 			res.GasFee = gasFee
@@ -558,7 +594,7 @@ func DeliverTx(ctx types.Context, store state.SimpleDB,
 		params := utils.GetParams()
 		gasUsed := params.UpgradeProgramProposalGas
 
-		if gasFee, err := checkGasFee(app_state, sender, gasUsed); err != nil {
+		if gasFee, err := checkGasFee(ctx, sender, gasUsed); err != nil {
 			return res, err
 		} else {
 			res.GasFee = gasFee
@@ -574,6 +610,9 @@ func DeliverTx(ctx types.Context, store state.SimpleDB,
 
 		DownloadProgramCmd(cp)
 
+	case TxUpgradeReady:
+		SaveUpgradeReady(&UpgradeReady{txInner.ProposalId, sender, ctx.BlockHeight()})
+
 	case TxVote:
 		var vote *Vote
 		if vote = GetVoteByPidAndVoter(txInner.ProposalId, sender.String()); vote != nil {
@@ -711,6 +750,47 @@ func CheckProposal(pid string, voter *common.Address) string {
 	return "not determined"
 }
 
+// CheckUpgradeReadiness tells whether the validators ready to run the new program version
+// of the upgrade proposal hold 2/3 of the voting power
+func CheckUpgradeReadiness(p *Proposal) *UpgradeStatus {
+	return upgradeStatus(p, GetUpgradeReadiesByPid(p.Id), stake.GetCandidates().Validators())
+}
+
+// QueryUpgradeStatus returns the readiness of the upgrade proposal, nil if it does not exist
+func QueryUpgradeStatus(pid string) *UpgradeStatus {
+	for _, p := range QueryProposals() {
+		if p.Id == pid && p.Type == UPGRADE_PROGRAM_PROPOSAL {
+			return upgradeStatus(p, QueryUpgradeReadiesByPid(pid), stake.QueryCandidates().Validators())
+		}
+	}
+	return nil
+}
+
+func upgradeStatus(p *Proposal, readies []*UpgradeReady, validators stake.Validators) *UpgradeStatus {
+	status := &UpgradeStatus{
+		ProposalId:      p.Id,
+		UpgradeHeight:   p.ExpireBlockHeight,
+		ReadyValidators: []common.Address{},
+	}
+	if v, ok := p.Detail["version"].(string); ok {
+		status.Version = v
+	}
+
+	for _, va := range validators {
+		for _, r := range readies {
+			if r.Validator.String() == va.OwnerAddress {
+				status.ReadyValidators = append(status.ReadyValidators, r.Validator)
+				status.ReadyPower += va.VotingPower
+			}
+		}
+		status.TotalPower += va.VotingPower
+	}
+
+	status.RequiredPower = (status.TotalPower*2 + 2) / 3
+	status.ThresholdMet = status.TotalPower > 0 && status.ReadyPower*3 >= status.TotalPower*2
+	return status
+}
+
 type ProposalReactor struct {
 	ProposalId  string
 	BlockHeight int64
@@ -737,16 +817,19 @@ func getTxSender(ctx types.Context) (sender common.Address, err error) {
 	return senders[0], nil
 }
 
-func checkGasFee(state *ethState.StateDB, address common.Address, gas uint64) (*big.Int, error) {
-	balance := state.GetBalance(address)
+func checkGasFee(ctx types.Context, address common.Address, gas uint64) (*big.Int, error) {
+	balance := ctx.EthappState().GetBalance(address)
 
-	gasFee := big.NewInt(0).Mul(big.NewInt(int64(gas)), big.NewInt(int64(utils.GetParams().GasPrice)))
+	gasFee, err := ctx.GasFee(gas)
+	if err != nil {
+		return nil, err
+	}
 
-	if balance.Cmp(gasFee) < 0 {
+	if balance.Cmp(gasFee.Int) < 0 {
 		return nil, ErrInsufficientBalance()
 	}
 
-	return gasFee, nil
+	return gasFee.Int, nil
 }
 
 func getOTAInfo(p *Proposal) *eni.OTAInfo {
@@ -870,31 +953,80 @@ func DownloadProgramCmd(p *Proposal) error {
 	if oi == nil {
 		return errors.New("unknown error")
 	}
-	info := &types.CmdInfo{Name: oi.LibName, Version: oi.Version, DownloadURLs:oi.Url, MD5: oi.Checksum}
+	info := types.NewCmdInfo(oi.LibName, oi.Version, oi.Url, oi.Checksum)
 	reply := &types.MonitorResponse{}
 	err := callRpc("Monitor.Download", info, reply)
 	if err != nil {
 		//log.Fatal("call monitor rpc error:", err)
 		return err
 	}
+	go waitProgramCmdReady(p, info.ReleaseName())
 	return nil
 }
 
+// UpgradeReadyReporter is called once the new program version of the upgrade proposal
+// is downloaded and verified, the node signals the readiness of its validator with it
+var UpgradeReadyReporter func(p *Proposal)
+
+// waitProgramCmdReady polls the monitor until the download of the release is done
+func waitProgramCmdReady(p *Proposal, release string) {
+	deadline := time.Now().Add(24 * time.Hour)
+	for time.Now().Before(deadline) {
+		time.Sleep(10 * time.Second)
+		status, err := ProgramCmdStatus(p)
+		if err != nil || status.Release != release || status.State == types.DownloadInProgress {
+			continue
+		}
+		if status.State == types.DownloadReady && UpgradeReadyReporter != nil {
+			UpgradeReadyReporter(p)
+		}
+		return
+	}
+}
+
 // UpgradeProgramCmd upgrade new program version
 func UpgradeProgramCmd(p *Proposal) error {
 	oi := getOTAInfo(p)
 	if oi == nil {
 		return errors.New("unknown error")
 	}
-	info := &types.CmdInfo{Name: oi.LibName, Version: oi.Version, DownloadURLs:oi.Url, MD5: oi.Checksum}
+	info := types.NewCmdInfo(oi.LibName, oi.Version, oi.Url, oi.Checksum)
+
+	// the monitor stops the node if the new version is missing, so leave it running instead
+	status, err := ProgramCmdStatus(p)
+	if err != nil {
+		return err
+	}
+	if !status.IsReady(info.ReleaseName()) {
+		return fmt.Errorf("%s is not ready to upgrade to: %s %s", info.ReleaseName(), status.State, status.Error)
+	}
+
 	reply := &types.MonitorResponse{}
-	err := callRpc("Monitor.Upgrade", info, reply)
+	err = callRpc("Monitor.Upgrade", info, reply)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// ProgramCmdStatus returns the download status of the new program version of the proposal
+func ProgramCmdStatus(p *Proposal) (*types.DownloadStatus, error) {
+	oi := getOTAInfo(p)
+	if oi == nil {
+		return nil, errors.New("unknown error")
+	}
+	info := types.NewCmdInfo(oi.LibName, oi.Version, oi.Url, oi.Checksum)
+	reply := &types.MonitorResponse{}
+	if err := callRpc("Monitor.Status", info, reply); err != nil {
+		return nil, err
+	}
+	status := &types.DownloadStatus{}
+	if err := json.Unmarshal(reply.Msg, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
 func callRpc(serviceMethod string, info *types.CmdInfo, reply *types.MonitorResponse) error {
 	client, err := rpc.DialHTTP("tcp", "127.0.0.1:26650")
 	if err != nil {
@@ -0,0 +1,52 @@
+package governance
+
+import (
+	"encoding/json"
+
+	"github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/utils"
+)
+
+var proposalMirror = state.NewMirror(utils.ProposalKey)
+
+// ProposalKey is the key of the proposal in the iavl store
+func ProposalKey(id string) []byte {
+	return append(append([]byte{}, utils.ProposalKey...), []byte(id)...)
+}
+
+// MirrorState copies the proposals changed in the block to the iavl store, so
+// they can be proven to the light clients. All of them are copied when the
+// mirror is seeded.
+func MirrorState(store state.SimpleDB, seed bool) {
+	if !seed {
+		proposalMirror.Update(store, loadMirroredProposal)
+		return
+	}
+
+	txWrapper := getSqlTxWrapper()
+	defer txWrapper.Commit()
+
+	values := make(map[string][]byte)
+	for _, p := range getProposals(txWrapper.tx) {
+		values[string(ProposalKey(p.Id))] = mirroredProposal(p)
+	}
+	proposalMirror.Seed(store, values)
+}
+
+func loadMirroredProposal(key []byte) []byte {
+	p := GetProposalById(string(key[len(utils.ProposalKey):]))
+	if p == nil {
+		return nil
+	}
+	return mirroredProposal(p)
+}
+
+// mirroredProposal leaves out the status of the libeni deployment, it's written
+// by each node when its download is done
+func mirroredProposal(p *Proposal) []byte {
+	if p.Type == DEPLOY_LIBENI_PROPOSAL && p.Detail != nil {
+		delete(p.Detail, "status")
+	}
+	b, _ := json.Marshal(p)
+	return b
+}
@@ -18,12 +18,14 @@ const (
 	ByteTxRetireProgramPropose     = 0xA4
 	ByteTxUpgradeProgramPropose    = 0xA5
 	ByteTxVote                     = 0xA6
+	ByteTxUpgradeReady             = 0xA7
 	TypeTxTransferFundPropose      = governanceModuleName + "/propose/transfer_fund"
 	TypeTxChangeParamPropose       = governanceModuleName + "/propose/change_param"
 	TypeTxDeployLibEniPropose      = governanceModuleName + "/propose/deploy_libeni"
 	TypeTxRetireProgramPropose     = governanceModuleName + "/propose/retire_program"
 	TypeTxUpgradeProgramPropose    = governanceModuleName + "/propose/upgrade_program"
 	TypeTxVote                     = governanceModuleName + "/vote"
+	TypeTxUpgradeReady             = governanceModuleName + "/upgrade_ready"
 )
 
 func init() {
@@ -33,11 +35,12 @@ func init() {
 	sdk.TxMapper.RegisterImplementation(TxRetireProgramPropose{}, TypeTxRetireProgramPropose, ByteTxRetireProgramPropose)
 	sdk.TxMapper.RegisterImplementation(TxUpgradeProgramPropose{}, TypeTxUpgradeProgramPropose, ByteTxUpgradeProgramPropose)
 	sdk.TxMapper.RegisterImplementation(TxVote{}, TypeTxVote, ByteTxVote)
+	sdk.TxMapper.RegisterImplementation(TxUpgradeReady{}, TypeTxUpgradeReady, ByteTxUpgradeReady)
 }
 
 //Verify interface at compile time
 var _, _, _, _, _ sdk.TxInner = &TxTransferFundPropose{}, &TxChangeParamPropose{}, &TxDeployLibEniPropose{}, &TxRetireProgramPropose{}, &TxUpgradeProgramPropose{}
-var _ sdk.TxInner = &TxVote{}
+var _, _ sdk.TxInner = &TxVote{}, &TxUpgradeReady{}
 
 type TxTransferFundPropose struct {
 	From               *common.Address   `json:"transfer_from"`
@@ -180,3 +183,20 @@ func NewTxVote(pid string, answer string) sdk.Tx {
 }
 
 func (tx TxVote) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxUpgradeReady is sent by a validator once the new program version of the upgrade proposal is staged
+type TxUpgradeReady struct {
+	ProposalId string `json:"proposal_id"`
+}
+
+func (tx TxUpgradeReady) ValidateBasic() error {
+	return nil
+}
+
+func NewTxUpgradeReady(pid string) sdk.Tx {
+	return TxUpgradeReady{
+		pid,
+	}.Wrap()
+}
+
+func (tx TxUpgradeReady) Wrap() sdk.Tx { return sdk.Tx{tx} }
@@ -193,3 +193,30 @@ func NewVote(proposalId string, voter common.Address, blockHeight int64, answer
 		answer,
 	}
 }
+
+// UpgradeReady records a validator has staged the new program version of an upgrade proposal
+type UpgradeReady struct {
+	ProposalId  string
+	Validator   common.Address
+	BlockHeight int64
+}
+
+func (r *UpgradeReady) Hash() []byte {
+	var excludedFields []string
+	bs := types.Hash(r, excludedFields)
+	hasher := ripemd160.New()
+	hasher.Write(bs)
+	return hasher.Sum(nil)
+}
+
+// UpgradeStatus compares the voting power of the validators ready to upgrade with the 2/3 needed
+type UpgradeStatus struct {
+	ProposalId      string           `json:"proposal_id"`
+	Version         string           `json:"version"`
+	UpgradeHeight   int64            `json:"upgrade_height"`
+	ReadyValidators []common.Address `json:"ready_validators"`
+	ReadyPower      int64            `json:"ready_power"`
+	TotalPower      int64            `json:"total_power"`
+	RequiredPower   int64            `json:"required_power"`
+	ThresholdMet    bool             `json:"threshold_met"`
+}
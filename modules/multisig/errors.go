@@ -0,0 +1,76 @@
+// nolint
+package multisig
+
+import (
+	"fmt"
+
+	"github.com/CyberMiles/travis/sdk/errors"
+)
+
+var (
+	errMissingSignature    = fmt.Errorf("Missing signature")
+	errDisabled            = fmt.Errorf("The multisig accounts are not enabled, the multisig_gas parameter must be set")
+	errBadMembers          = fmt.Errorf("The members must be distinct non-zero addresses, at most %d of them", maxMembers)
+	errBadThreshold        = fmt.Errorf("The threshold must be between 1 and the number of members")
+	errBadInnerTx          = fmt.Errorf("Only the stake and governance txs can be sent by a multisig account")
+	errMultisigExists      = fmt.Errorf("The multisig account already exists")
+	errNoMultisig          = fmt.Errorf("The multisig account doesn't exist")
+	errNotMember           = fmt.Errorf("The sender is not a member of the multisig account")
+	errNoMultisigTx        = fmt.Errorf("The multisig tx doesn't exist")
+	errAlreadyApproved     = fmt.Errorf("The sender already approved the multisig tx")
+	errAlreadyExecuted     = fmt.Errorf("The multisig tx has been executed")
+	errThresholdNotReached = fmt.Errorf("The multisig tx has not been approved by enough members")
+	errInsufficientBalance = fmt.Errorf("Insufficient balance")
+)
+
+func ErrMissingSignature() error {
+	return errors.WithCode(errMissingSignature, errors.CodeTypeUnauthorized)
+}
+
+func ErrDisabled() error {
+	return errors.WithCode(errDisabled, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrBadMembers() error {
+	return errors.WithCode(errBadMembers, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrBadThreshold() error {
+	return errors.WithCode(errBadThreshold, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrBadInnerTx() error {
+	return errors.WithCode(errBadInnerTx, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrMultisigExists() error {
+	return errors.WithCode(errMultisigExists, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrNoMultisig() error {
+	return errors.WithCode(errNoMultisig, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrNotMember() error {
+	return errors.WithCode(errNotMember, errors.CodeTypeUnauthorized)
+}
+
+func ErrNoMultisigTx() error {
+	return errors.WithCode(errNoMultisigTx, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrAlreadyApproved() error {
+	return errors.WithCode(errAlreadyApproved, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrAlreadyExecuted() error {
+	return errors.WithCode(errAlreadyExecuted, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrThresholdNotReached() error {
+	return errors.WithCode(errThresholdNotReached, errors.CodeTypeUnauthorized)
+}
+
+func ErrInsufficientBalance() error {
+	return errors.WithCode(errInsufficientBalance, errors.CodeTypeBaseInvalidInput)
+}
@@ -0,0 +1,56 @@
+package multisig
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	sm "github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/types"
+	"github.com/CyberMiles/travis/utils"
+)
+
+// ExportGenesis adds the multisig accounts to the genesis, the txs proposed to
+// them are not exported, the pending ones have to be proposed again
+func ExportGenesis(store sm.SimpleDB, genDoc *types.GenesisDoc) {
+	start := utils.MultisigKey
+	for _, m := range store.List(start, sm.PrefixEnd(start), 0) {
+		if len(m.Key) != len(start)+common.AddressLength {
+			continue // tx of a multisig account
+		}
+		ms := new(Multisig)
+		if err := json.Unmarshal(m.Value, ms); err != nil {
+			continue
+		}
+		members := make([]string, len(ms.Members))
+		for i, member := range ms.Members {
+			members[i] = member.String()
+		}
+		genDoc.Multisigs = append(genDoc.Multisigs, types.GenesisMultisig{
+			Address:   ms.Address.String(),
+			Creator:   ms.Creator.String(),
+			Members:   members,
+			Threshold: ms.Threshold,
+		})
+	}
+}
+
+// ImportGenesis restores the multisig accounts exported from a running chain
+func ImportGenesis(store sm.SimpleDB, genDoc *types.GenesisDoc) error {
+	for _, gm := range genDoc.Multisigs {
+		members := make([]common.Address, len(gm.Members))
+		for i, member := range gm.Members {
+			members[i] = common.HexToAddress(member)
+		}
+		if err := (TxCreateMultisig{members, gm.Threshold}).ValidateBasic(); err != nil {
+			return err
+		}
+		saveMultisig(store, &Multisig{
+			Address:   common.HexToAddress(gm.Address),
+			Creator:   common.HexToAddress(gm.Creator),
+			Members:   members,
+			Threshold: gm.Threshold,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package multisig
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/CyberMiles/travis/sdk"
+	"github.com/CyberMiles/travis/sdk/errors"
+	"github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/types"
+	"github.com/CyberMiles/travis/utils"
+)
+
+// nolint
+const multisigModuleName = "multisig"
+
+// Name is the name of the modules.
+func Name() string {
+	return multisigModuleName
+}
+
+// Executor delivers the tx of a multisig account to the module it's routed to,
+// the context has the multisig account as the sender
+type Executor func(ctx types.Context, tx sdk.Tx) (sdk.DeliverResult, error)
+
+// CheckTx validates the multisig tx against the current state
+func CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
+	_, _, err = verify(ctx, store, tx)
+	return
+}
+
+// DeliverTx executes the multisig tx, the approved txs are delivered by exec
+func DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx, hash []byte, exec Executor) (res sdk.DeliverResult, err error) {
+	sender, gasFee, err := verify(ctx, store, tx)
+	if err != nil {
+		return
+	}
+	res.GasFee = new(big.Int).Set(gasFee)
+	res.GasUsed = int64(utils.GetParams().MultisigGas)
+	res.Data = hash
+
+	switch txInner := tx.Unwrap().(type) {
+	case TxCreateMultisig:
+		m := &Multisig{
+			Address:   MultisigAddress(sender, ctx.GetNonce()),
+			Creator:   sender,
+			Members:   txInner.Members,
+			Threshold: txInner.Threshold,
+			CreatedAt: ctx.BlockHeight(),
+		}
+		saveMultisig(store, m)
+		res.Data = m.Address.Bytes()
+	case TxProposeMultisig:
+		m := GetMultisig(store, txInner.Multisig)
+		mtx := &MultisigTx{
+			Id:        m.NextTxId,
+			Tx:        txInner.Tx,
+			Proposer:  sender,
+			Approvals: []common.Address{sender},
+			CreatedAt: ctx.BlockHeight(),
+		}
+		m.NextTxId++
+		saveMultisig(store, m)
+		saveMultisigTx(store, m.Address, mtx)
+		res.Data = []byte(strconv.FormatUint(mtx.Id, 10))
+	case TxApproveMultisig:
+		mtx := GetMultisigTx(store, txInner.Multisig, txInner.Id)
+		mtx.Approvals = append(mtx.Approvals, sender)
+		saveMultisigTx(store, txInner.Multisig, mtx)
+	case TxExecuteMultisig:
+		mtx := GetMultisigTx(store, txInner.Multisig, txInner.Id)
+		// the executed tx pays its own gas from the multisig account
+		innerRes, innerErr := exec(ctx.WithSender(txInner.Multisig), mtx.Tx)
+		if innerErr != nil {
+			return res, innerErr
+		}
+		mtx.ExecutedAt = ctx.BlockHeight()
+		saveMultisigTx(store, txInner.Multisig, mtx)
+		if innerRes.GasFee != nil {
+			res.GasFee.Add(res.GasFee, innerRes.GasFee)
+		}
+		res.GasUsed += innerRes.GasUsed
+		if innerRes.Data != nil {
+			res.Data = innerRes.Data
+		}
+	}
+
+	// transfer gasFee
+	appState := ctx.EthappState()
+	appState.SubBalance(sender, gasFee)
+	appState.AddBalance(utils.HoldAccount, gasFee)
+	return
+}
+
+// verify checks the tx can be applied and the sender can pay for it, it returns the sender and the gas fee
+func verify(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (sender common.Address, gasFee *big.Int, err error) {
+	if err = tx.ValidateBasic(); err != nil {
+		return
+	}
+
+	senders := ctx.GetSigners()
+	if len(senders) != 1 {
+		err = ErrMissingSignature()
+		return
+	}
+	sender = senders[0]
+
+	// disabled until the gas is set by a proposal on the chains started before
+	params := utils.GetParams()
+	if params.MultisigGas == 0 {
+		err = ErrDisabled()
+		return
+	}
+	fee, err := ctx.GasFee(params.MultisigGas)
+	if err != nil {
+		return
+	}
+	gasFee = fee.Int
+
+	switch txInner := tx.Unwrap().(type) {
+	case TxCreateMultisig:
+		if GetMultisig(store, MultisigAddress(sender, ctx.GetNonce())) != nil {
+			err = ErrMultisigExists()
+			return
+		}
+	case TxProposeMultisig:
+		if err = checkMember(store, txInner.Multisig, sender); err != nil {
+			return
+		}
+	case TxApproveMultisig:
+		if err = checkMember(store, txInner.Multisig, sender); err != nil {
+			return
+		}
+		var mtx *MultisigTx
+		if mtx, err = getPendingTx(store, txInner.Multisig, txInner.Id); err != nil {
+			return
+		}
+		if mtx.HasApproved(sender) {
+			err = ErrAlreadyApproved()
+			return
+		}
+	case TxExecuteMultisig:
+		if err = checkMember(store, txInner.Multisig, sender); err != nil {
+			return
+		}
+		var mtx *MultisigTx
+		if mtx, err = getPendingTx(store, txInner.Multisig, txInner.Id); err != nil {
+			return
+		}
+		if len(mtx.Approvals) < GetMultisig(store, txInner.Multisig).Threshold {
+			err = ErrThresholdNotReached()
+			return
+		}
+	default:
+		err = errors.ErrUnknownTxType(tx)
+		return
+	}
+
+	if ctx.EthappState().GetBalance(sender).Cmp(gasFee) < 0 {
+		err = ErrInsufficientBalance()
+	}
+	return
+}
+
+func checkMember(store state.SimpleDB, address, sender common.Address) error {
+	m := GetMultisig(store, address)
+	if m == nil {
+		return ErrNoMultisig()
+	}
+	if !m.IsMember(sender) {
+		return ErrNotMember()
+	}
+	return nil
+}
+
+func getPendingTx(store state.SimpleDB, address common.Address, id uint64) (*MultisigTx, error) {
+	mtx := GetMultisigTx(store, address, id)
+	if mtx == nil {
+		return nil, ErrNoMultisigTx()
+	}
+	if mtx.ExecutedAt > 0 {
+		return nil, ErrAlreadyExecuted()
+	}
+	return mtx, nil
+}
@@ -0,0 +1,70 @@
+package multisig
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CyberMiles/travis/modules/stake"
+	"github.com/CyberMiles/travis/sdk"
+	sm "github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/types"
+	"github.com/CyberMiles/travis/utils"
+)
+
+func TestMultisigApprovals(t *testing.T) {
+	assert := assert.New(t)
+	utils.SetParams(utils.DefaultParams())
+
+	store := sm.NewMemKVStore()
+	ethState, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	alice := common.HexToAddress("0x01")
+	bob := common.HexToAddress("0x02")
+	carol := common.HexToAddress("0x03")
+	eve := common.HexToAddress("0x04")
+	for _, a := range []common.Address{alice, bob, carol, eve} {
+		ethState.AddBalance(a, big.NewInt(1e18))
+	}
+
+	var executed []common.Address
+	exec := func(ctx types.Context, tx sdk.Tx) (sdk.DeliverResult, error) {
+		executed = append(executed, ctx.GetSigners()...)
+		return sdk.DeliverResult{GasFee: big.NewInt(0)}, nil
+	}
+	deliver := func(sender common.Address, nonce uint64, tx sdk.Tx) error {
+		ctx := types.NewContext("", 1, 0, ethState)
+		ctx.WithSigners(sender)
+		ctx.SetNonce(nonce)
+		_, err := DeliverTx(ctx, store, tx, nil, exec)
+		return err
+	}
+
+	assert.NotNil(deliver(alice, 0, NewTxCreateMultisig([]common.Address{alice, bob, carol}, 4)))
+	assert.NotNil(deliver(alice, 0, NewTxCreateMultisig([]common.Address{alice, alice}, 1)))
+	assert.Nil(deliver(alice, 0, NewTxCreateMultisig([]common.Address{alice, bob, carol}, 2)))
+	multisig := MultisigAddress(alice, 0)
+	assert.NotNil(GetMultisig(store, multisig))
+
+	inner := stake.NewTxDelegate(common.HexToAddress("0x05"), "1000", "", "", "")
+	assert.NotNil(deliver(eve, 0, NewTxProposeMultisig(multisig, inner)))
+	assert.Nil(deliver(bob, 0, NewTxProposeMultisig(multisig, inner)))
+
+	// one approval of the two required
+	assert.NotNil(deliver(bob, 1, NewTxExecuteMultisig(multisig, 0)))
+	assert.NotNil(deliver(bob, 1, NewTxApproveMultisig(multisig, 0)))
+	assert.NotNil(deliver(eve, 1, NewTxApproveMultisig(multisig, 0)))
+	assert.Nil(deliver(carol, 0, NewTxApproveMultisig(multisig, 0)))
+
+	// executed once with the multisig account as the sender
+	assert.Nil(deliver(alice, 1, NewTxExecuteMultisig(multisig, 0)))
+	assert.Equal([]common.Address{multisig}, executed)
+	assert.NotNil(deliver(alice, 2, NewTxExecuteMultisig(multisig, 0)))
+
+	mtxs := GetMultisigTxs(store, multisig)
+	assert.Equal(1, len(mtxs))
+	assert.Equal(int64(1), mtxs[0].ExecutedAt)
+}
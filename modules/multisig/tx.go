@@ -0,0 +1,141 @@
+package multisig
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/CyberMiles/travis/sdk"
+)
+
+// Tx
+//--------------------------------------------------------------------------------
+
+// register the tx type with its validation logic
+// make sure to use the name of the handler as the prefix in the tx type,
+// so it gets routed properly
+const (
+	ByteTxCreateMultisig  = 0xC1
+	ByteTxProposeMultisig = 0xC2
+	ByteTxApproveMultisig = 0xC3
+	ByteTxExecuteMultisig = 0xC4
+	TypeTxCreateMultisig  = multisigModuleName + "/create"
+	TypeTxProposeMultisig = multisigModuleName + "/propose"
+	TypeTxApproveMultisig = multisigModuleName + "/approve"
+	TypeTxExecuteMultisig = multisigModuleName + "/execute"
+)
+
+// maximum number of members of a multisig account
+const maxMembers = 32
+
+func init() {
+	sdk.TxMapper.RegisterImplementation(TxCreateMultisig{}, TypeTxCreateMultisig, ByteTxCreateMultisig)
+	sdk.TxMapper.RegisterImplementation(TxProposeMultisig{}, TypeTxProposeMultisig, ByteTxProposeMultisig)
+	sdk.TxMapper.RegisterImplementation(TxApproveMultisig{}, TypeTxApproveMultisig, ByteTxApproveMultisig)
+	sdk.TxMapper.RegisterImplementation(TxExecuteMultisig{}, TypeTxExecuteMultisig, ByteTxExecuteMultisig)
+}
+
+// Verify interface at compile time
+var _, _, _, _ sdk.TxInner = &TxCreateMultisig{}, &TxProposeMultisig{}, &TxApproveMultisig{}, &TxExecuteMultisig{}
+
+// TxCreateMultisig creates an account controlled by M of its N members, its
+// address is derived from the sender and the nonce of the tx
+type TxCreateMultisig struct {
+	Members   []common.Address `json:"members"`
+	Threshold int              `json:"threshold"`
+}
+
+func (tx TxCreateMultisig) ValidateBasic() error {
+	if len(tx.Members) == 0 || len(tx.Members) > maxMembers {
+		return ErrBadMembers()
+	}
+	seen := make(map[common.Address]bool)
+	for _, m := range tx.Members {
+		if m == (common.Address{}) || seen[m] {
+			return ErrBadMembers()
+		}
+		seen[m] = true
+	}
+	if tx.Threshold < 1 || tx.Threshold > len(tx.Members) {
+		return ErrBadThreshold()
+	}
+	return nil
+}
+
+func NewTxCreateMultisig(members []common.Address, threshold int) sdk.Tx {
+	return TxCreateMultisig{
+		Members:   members,
+		Threshold: threshold,
+	}.Wrap()
+}
+
+func (tx TxCreateMultisig) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxProposeMultisig proposes a tx to be sent by the multisig account, it counts
+// as the approval of the member proposing it
+type TxProposeMultisig struct {
+	Multisig common.Address `json:"multisig"`
+	Tx       sdk.Tx         `json:"tx"`
+}
+
+func (tx TxProposeMultisig) ValidateBasic() error {
+	if tx.Tx.Empty() {
+		return ErrBadInnerTx()
+	}
+	kind, err := tx.Tx.GetKind()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(kind, "stake/") && !strings.HasPrefix(kind, "governance/") {
+		return ErrBadInnerTx()
+	}
+	return tx.Tx.ValidateBasic()
+}
+
+func NewTxProposeMultisig(multisig common.Address, inner sdk.Tx) sdk.Tx {
+	return TxProposeMultisig{
+		Multisig: multisig,
+		Tx:       inner,
+	}.Wrap()
+}
+
+func (tx TxProposeMultisig) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxApproveMultisig adds the approval of a member to a proposed tx
+type TxApproveMultisig struct {
+	Multisig common.Address `json:"multisig"`
+	Id       uint64         `json:"id"`
+}
+
+func (tx TxApproveMultisig) ValidateBasic() error {
+	return nil
+}
+
+func NewTxApproveMultisig(multisig common.Address, id uint64) sdk.Tx {
+	return TxApproveMultisig{
+		Multisig: multisig,
+		Id:       id,
+	}.Wrap()
+}
+
+func (tx TxApproveMultisig) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxExecuteMultisig sends a proposed tx approved by enough members, with the
+// multisig account as the sender, any member can execute it
+type TxExecuteMultisig struct {
+	Multisig common.Address `json:"multisig"`
+	Id       uint64         `json:"id"`
+}
+
+func (tx TxExecuteMultisig) ValidateBasic() error {
+	return nil
+}
+
+func NewTxExecuteMultisig(multisig common.Address, id uint64) sdk.Tx {
+	return TxExecuteMultisig{
+		Multisig: multisig,
+		Id:       id,
+	}.Wrap()
+}
+
+func (tx TxExecuteMultisig) Wrap() sdk.Tx { return sdk.Tx{tx} }
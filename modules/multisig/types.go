@@ -0,0 +1,133 @@
+package multisig
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/CyberMiles/travis/sdk"
+	sm "github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/utils"
+)
+
+// Multisig is an account controlled by its members, a tx is sent by it once
+// Threshold of them approved it
+type Multisig struct {
+	Address   common.Address   `json:"address"`
+	Creator   common.Address   `json:"creator"`
+	Members   []common.Address `json:"members"`
+	Threshold int              `json:"threshold"`
+	NextTxId  uint64           `json:"next_tx_id"`
+	CreatedAt int64            `json:"created_at"`
+}
+
+// IsMember tells whether the address is one of the members
+func (m *Multisig) IsMember(address common.Address) bool {
+	for _, member := range m.Members {
+		if member == address {
+			return true
+		}
+	}
+	return false
+}
+
+// MultisigTx is a tx proposed to be sent by a multisig account
+type MultisigTx struct {
+	Id         uint64           `json:"id"`
+	Tx         sdk.Tx           `json:"tx"`
+	Proposer   common.Address   `json:"proposer"`
+	Approvals  []common.Address `json:"approvals"`
+	CreatedAt  int64            `json:"created_at"`
+	ExecutedAt int64            `json:"executed_at"` // 0 until it's executed
+}
+
+// HasApproved tells whether the member approved the tx
+func (mtx *MultisigTx) HasApproved(member common.Address) bool {
+	for _, a := range mtx.Approvals {
+		if a == member {
+			return true
+		}
+	}
+	return false
+}
+
+// MultisigInfo is the multisig account along with the txs proposed to it
+type MultisigInfo struct {
+	*Multisig
+	Txs []*MultisigTx `json:"txs"`
+}
+
+// MultisigAddress is the address of the multisig account created by the tx of the creator with the nonce
+func MultisigAddress(creator common.Address, nonce uint64) common.Address {
+	n := make([]byte, 8)
+	binary.BigEndian.PutUint64(n, nonce)
+	return common.BytesToAddress(crypto.Keccak256([]byte(multisigModuleName), creator.Bytes(), n)[12:])
+}
+
+func multisigKey(address common.Address) []byte {
+	return append(append([]byte{}, utils.MultisigKey...), address.Bytes()...)
+}
+
+func multisigTxKey(address common.Address, id uint64) []byte {
+	n := make([]byte, 8)
+	binary.BigEndian.PutUint64(n, id)
+	return append(multisigKey(address), n...)
+}
+
+// GetMultisig returns the multisig account, nil if there is none at the address
+func GetMultisig(store sm.SimpleDB, address common.Address) *Multisig {
+	b := store.Get(multisigKey(address))
+	if b == nil {
+		return nil
+	}
+
+	m := new(Multisig)
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil
+	}
+	return m
+}
+
+func saveMultisig(store sm.SimpleDB, m *Multisig) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(multisigKey(m.Address), b)
+}
+
+// GetMultisigTx returns the tx proposed to the multisig account, nil if there is none with the id
+func GetMultisigTx(store sm.SimpleDB, address common.Address, id uint64) *MultisigTx {
+	b := store.Get(multisigTxKey(address, id))
+	if b == nil {
+		return nil
+	}
+
+	mtx := new(MultisigTx)
+	if err := json.Unmarshal(b, mtx); err != nil {
+		return nil
+	}
+	return mtx
+}
+
+// GetMultisigTxs returns all the txs proposed to the multisig account
+func GetMultisigTxs(store sm.SimpleDB, address common.Address) (mtxs []*MultisigTx) {
+	start := multisigTxKey(address, 0)
+	for _, m := range store.List(start, sm.PrefixEnd(multisigKey(address)), 0) {
+		mtx := new(MultisigTx)
+		if err := json.Unmarshal(m.Value, mtx); err == nil {
+			mtxs = append(mtxs, mtx)
+		}
+	}
+	return
+}
+
+func saveMultisigTx(store sm.SimpleDB, address common.Address, mtx *MultisigTx) {
+	b, err := json.Marshal(mtx)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(multisigTxKey(address, mtx.Id), b)
+}
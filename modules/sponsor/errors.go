@@ -0,0 +1,76 @@
+// nolint
+package sponsor
+
+import (
+	"fmt"
+
+	"github.com/CyberMiles/travis/sdk/errors"
+)
+
+var (
+	errMissingSignature      = fmt.Errorf("Missing signature")
+	errDisabled              = fmt.Errorf("The gas sponsorships are not enabled, the sponsorship_gas parameter must be set")
+	errBadAmount             = fmt.Errorf("Amount must be a non-negative integer in wei")
+	errBadLimit              = fmt.Errorf("Sponsorship limits must be non-negative integers in wei")
+	errNotContractCreator    = fmt.Errorf("The sender did not create the contract with the given nonce")
+	errNotContract           = fmt.Errorf("The address is not a contract")
+	errSponsorshipExists     = fmt.Errorf("The contract is already sponsored")
+	errNoSponsorship         = fmt.Errorf("The contract is not sponsored")
+	errNotSponsorshipOwner   = fmt.Errorf("Only the owner can manage the sponsorship")
+	errInsufficientBalance   = fmt.Errorf("Insufficient balance")
+	errInsufficientSponsored = fmt.Errorf("The sponsorship balance of the contract is insufficient")
+	errUserLimitReached      = fmt.Errorf("The sponsored fee limit of the user is reached")
+	errBlockLimitReached     = fmt.Errorf("The sponsored fee limit of the block is reached")
+)
+
+func ErrMissingSignature() error {
+	return errors.WithCode(errMissingSignature, errors.CodeTypeUnauthorized)
+}
+
+func ErrDisabled() error {
+	return errors.WithCode(errDisabled, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrBadAmount() error {
+	return errors.WithCode(errBadAmount, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrBadLimit() error {
+	return errors.WithCode(errBadLimit, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrNotContractCreator() error {
+	return errors.WithCode(errNotContractCreator, errors.CodeTypeUnauthorized)
+}
+
+func ErrNotContract() error {
+	return errors.WithCode(errNotContract, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrSponsorshipExists() error {
+	return errors.WithCode(errSponsorshipExists, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrNoSponsorship() error {
+	return errors.WithCode(errNoSponsorship, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrNotSponsorshipOwner() error {
+	return errors.WithCode(errNotSponsorshipOwner, errors.CodeTypeUnauthorized)
+}
+
+func ErrInsufficientBalance() error {
+	return errors.WithCode(errInsufficientBalance, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrInsufficientSponsored() error {
+	return errors.WithCode(errInsufficientSponsored, errors.CodeHighGasLimitErr)
+}
+
+func ErrUserLimitReached() error {
+	return errors.WithCode(errUserLimitReached, errors.CodeHighGasLimitErr)
+}
+
+func ErrBlockLimitReached() error {
+	return errors.WithCode(errBlockLimitReached, errors.CodeHighGasLimitErr)
+}
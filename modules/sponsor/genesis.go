@@ -0,0 +1,58 @@
+package sponsor
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	sm "github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/types"
+	"github.com/CyberMiles/travis/utils"
+)
+
+// ExportGenesis adds the sponsorships to the genesis, the usage windows of the
+// users start over after the import
+func ExportGenesis(store sm.SimpleDB, genDoc *types.GenesisDoc) {
+	start := utils.SponsorshipKey
+	end := []byte{start[0] + 1}
+	for _, m := range store.List(start, end, 0) {
+		if len(m.Key) != len(start)+common.AddressLength {
+			continue // usage of a user
+		}
+		s := new(Sponsorship)
+		if err := json.Unmarshal(m.Value, s); err != nil {
+			continue
+		}
+		genDoc.Sponsorships = append(genDoc.Sponsorships, types.GenesisSponsorship{
+			Contract:       s.Contract.String(),
+			Owner:          s.Owner.String(),
+			Balance:        s.Balance,
+			MaxFeePerUser:  s.MaxFeePerUser,
+			MaxFeePerBlock: s.MaxFeePerBlock,
+			TotalSponsored: s.TotalSponsored,
+		})
+	}
+}
+
+// ImportGenesis restores the sponsorships exported from a running chain, their
+// balances stay in the hold account of the exported vm state
+func ImportGenesis(store sm.SimpleDB, genDoc *types.GenesisDoc) error {
+	for _, gs := range genDoc.Sponsorships {
+		if _, ok := parseAmount(gs.Balance); !ok {
+			return ErrBadAmount()
+		}
+		if err := validateLimits(gs.MaxFeePerUser, gs.MaxFeePerBlock); err != nil {
+			return err
+		}
+		saveSponsorship(store, &Sponsorship{
+			Contract:       common.HexToAddress(gs.Contract),
+			Owner:          common.HexToAddress(gs.Owner),
+			Balance:        gs.Balance,
+			MaxFeePerUser:  gs.MaxFeePerUser,
+			MaxFeePerBlock: gs.MaxFeePerBlock,
+			TotalSponsored: gs.TotalSponsored,
+			LastSponsored:  "0",
+		})
+	}
+	return nil
+}
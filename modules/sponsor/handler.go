@@ -0,0 +1,166 @@
+package sponsor
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/CyberMiles/travis/sdk"
+	"github.com/CyberMiles/travis/sdk/errors"
+	"github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/types"
+	"github.com/CyberMiles/travis/utils"
+)
+
+// nolint
+const sponsorModuleName = "sponsor"
+
+// Name is the name of the modules.
+func Name() string {
+	return sponsorModuleName
+}
+
+// CheckTx validates the sponsorship tx against the current state
+func CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
+	_, _, err = verify(ctx, store, tx)
+	return
+}
+
+// DeliverTx executes the sponsorship tx
+func DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx, hash []byte) (res sdk.DeliverResult, err error) {
+	sender, gasFee, err := verify(ctx, store, tx)
+	if err != nil {
+		return
+	}
+
+	appState := ctx.EthappState()
+	switch txInner := tx.Unwrap().(type) {
+	case TxOpenSponsorship:
+		amount, _ := parseAmount(txInner.Amount)
+		appState.SubBalance(sender, amount)
+		appState.AddBalance(utils.SponsorAccount, amount)
+		saveSponsorship(store, &Sponsorship{
+			Contract:       txInner.Contract,
+			Owner:          sender,
+			Balance:        amount.String(),
+			MaxFeePerUser:  txInner.MaxFeePerUser,
+			MaxFeePerBlock: txInner.MaxFeePerBlock,
+			TotalSponsored: "0",
+			LastSponsored:  "0",
+			CreatedAt:      ctx.BlockHeight(),
+		})
+	case TxFundSponsorship:
+		amount, _ := parseAmount(txInner.Amount)
+		appState.SubBalance(sender, amount)
+		appState.AddBalance(utils.SponsorAccount, amount)
+		s := GetSponsorship(store, txInner.Contract)
+		s.Balance = new(big.Int).Add(toBig(s.Balance), amount).String()
+		saveSponsorship(store, s)
+	case TxSetSponsorshipLimits:
+		s := GetSponsorship(store, txInner.Contract)
+		s.MaxFeePerUser = txInner.MaxFeePerUser
+		s.MaxFeePerBlock = txInner.MaxFeePerBlock
+		saveSponsorship(store, s)
+	case TxWithdrawSponsorship:
+		amount, _ := parseAmount(txInner.Amount)
+		appState.SubBalance(utils.SponsorAccount, amount)
+		appState.AddBalance(sender, amount)
+		s := GetSponsorship(store, txInner.Contract)
+		s.Balance = new(big.Int).Sub(toBig(s.Balance), amount).String()
+		saveSponsorship(store, s)
+	}
+
+	// transfer gasFee
+	appState.SubBalance(sender, gasFee)
+	appState.AddBalance(utils.HoldAccount, gasFee)
+	res.GasFee = gasFee
+	res.GasUsed = int64(utils.GetParams().SponsorshipGas)
+	res.Data = hash
+	return
+}
+
+// verify checks the tx can be applied and the sender can pay for it, it returns the sender and the gas fee
+func verify(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (sender common.Address, gasFee *big.Int, err error) {
+	if err = tx.ValidateBasic(); err != nil {
+		return
+	}
+
+	senders := ctx.GetSigners()
+	if len(senders) != 1 {
+		err = ErrMissingSignature()
+		return
+	}
+	sender = senders[0]
+
+	// disabled until the gas is set by a proposal on the chains started before
+	params := utils.GetParams()
+	if params.SponsorshipGas == 0 {
+		err = ErrDisabled()
+		return
+	}
+	fee, err := ctx.GasFee(params.SponsorshipGas)
+	if err != nil {
+		return
+	}
+	gasFee = fee.Int
+
+	appState := ctx.EthappState()
+	cost := new(big.Int).Set(gasFee)
+	switch txInner := tx.Unwrap().(type) {
+	case TxOpenSponsorship:
+		if crypto.CreateAddress(sender, txInner.CreationNonce) != txInner.Contract {
+			err = ErrNotContractCreator()
+			return
+		}
+		if len(appState.GetCode(txInner.Contract)) == 0 {
+			err = ErrNotContract()
+			return
+		}
+		if GetSponsorship(store, txInner.Contract) != nil {
+			err = ErrSponsorshipExists()
+			return
+		}
+		amount, _ := parseAmount(txInner.Amount)
+		cost.Add(cost, amount)
+	case TxFundSponsorship:
+		if GetSponsorship(store, txInner.Contract) == nil {
+			err = ErrNoSponsorship()
+			return
+		}
+		amount, _ := parseAmount(txInner.Amount)
+		cost.Add(cost, amount)
+	case TxSetSponsorshipLimits:
+		if err = checkOwner(store, txInner.Contract, sender); err != nil {
+			return
+		}
+	case TxWithdrawSponsorship:
+		if err = checkOwner(store, txInner.Contract, sender); err != nil {
+			return
+		}
+		amount, _ := parseAmount(txInner.Amount)
+		if toBig(GetSponsorship(store, txInner.Contract).Balance).Cmp(amount) < 0 {
+			err = ErrInsufficientSponsored()
+			return
+		}
+	default:
+		err = errors.ErrUnknownTxType(tx)
+		return
+	}
+
+	if appState.GetBalance(sender).Cmp(cost) < 0 {
+		err = ErrInsufficientBalance()
+	}
+	return
+}
+
+func checkOwner(store state.SimpleDB, contract, sender common.Address) error {
+	s := GetSponsorship(store, contract)
+	if s == nil {
+		return ErrNoSponsorship()
+	}
+	if s.Owner != sender {
+		return ErrNotSponsorshipOwner()
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package sponsor
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/CyberMiles/travis/sdk"
+)
+
+// Tx
+//--------------------------------------------------------------------------------
+
+// register the tx type with its validation logic
+// make sure to use the name of the handler as the prefix in the tx type,
+// so it gets routed properly
+const (
+	ByteTxOpenSponsorship      = 0xB1
+	ByteTxFundSponsorship      = 0xB2
+	ByteTxSetSponsorshipLimits = 0xB3
+	ByteTxWithdrawSponsorship  = 0xB4
+	TypeTxOpenSponsorship      = sponsorModuleName + "/open"
+	TypeTxFundSponsorship      = sponsorModuleName + "/fund"
+	TypeTxSetSponsorshipLimits = sponsorModuleName + "/setLimits"
+	TypeTxWithdrawSponsorship  = sponsorModuleName + "/withdraw"
+)
+
+func init() {
+	sdk.TxMapper.RegisterImplementation(TxOpenSponsorship{}, TypeTxOpenSponsorship, ByteTxOpenSponsorship)
+	sdk.TxMapper.RegisterImplementation(TxFundSponsorship{}, TypeTxFundSponsorship, ByteTxFundSponsorship)
+	sdk.TxMapper.RegisterImplementation(TxSetSponsorshipLimits{}, TypeTxSetSponsorshipLimits, ByteTxSetSponsorshipLimits)
+	sdk.TxMapper.RegisterImplementation(TxWithdrawSponsorship{}, TypeTxWithdrawSponsorship, ByteTxWithdrawSponsorship)
+}
+
+// Verify interface at compile time
+var _, _, _, _ sdk.TxInner = &TxOpenSponsorship{}, &TxFundSponsorship{}, &TxSetSponsorshipLimits{}, &TxWithdrawSponsorship{}
+
+// TxOpenSponsorship opts a contract in the gas sponsorship, it must be sent by
+// the account which created the contract with the given nonce
+type TxOpenSponsorship struct {
+	Contract       common.Address `json:"contract"`
+	CreationNonce  uint64         `json:"creation_nonce"`
+	Amount         string         `json:"amount"`
+	MaxFeePerUser  string         `json:"max_fee_per_user"`
+	MaxFeePerBlock string         `json:"max_fee_per_block"`
+}
+
+func (tx TxOpenSponsorship) ValidateBasic() error {
+	if _, ok := parseAmount(tx.Amount); !ok {
+		return ErrBadAmount()
+	}
+	return validateLimits(tx.MaxFeePerUser, tx.MaxFeePerBlock)
+}
+
+func NewTxOpenSponsorship(contract common.Address, creationNonce uint64, amount, maxFeePerUser, maxFeePerBlock string) sdk.Tx {
+	return TxOpenSponsorship{
+		Contract:       contract,
+		CreationNonce:  creationNonce,
+		Amount:         amount,
+		MaxFeePerUser:  maxFeePerUser,
+		MaxFeePerBlock: maxFeePerBlock,
+	}.Wrap()
+}
+
+func (tx TxOpenSponsorship) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxFundSponsorship tops up the sponsorship balance of a contract, anyone can fund it
+type TxFundSponsorship struct {
+	Contract common.Address `json:"contract"`
+	Amount   string         `json:"amount"`
+}
+
+func (tx TxFundSponsorship) ValidateBasic() error {
+	if amount, ok := parseAmount(tx.Amount); !ok || amount.Sign() == 0 {
+		return ErrBadAmount()
+	}
+	return nil
+}
+
+func NewTxFundSponsorship(contract common.Address, amount string) sdk.Tx {
+	return TxFundSponsorship{
+		Contract: contract,
+		Amount:   amount,
+	}.Wrap()
+}
+
+func (tx TxFundSponsorship) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxSetSponsorshipLimits changes the limits of the sponsorship, only the owner can send it
+type TxSetSponsorshipLimits struct {
+	Contract       common.Address `json:"contract"`
+	MaxFeePerUser  string         `json:"max_fee_per_user"`
+	MaxFeePerBlock string         `json:"max_fee_per_block"`
+}
+
+func (tx TxSetSponsorshipLimits) ValidateBasic() error {
+	return validateLimits(tx.MaxFeePerUser, tx.MaxFeePerBlock)
+}
+
+func NewTxSetSponsorshipLimits(contract common.Address, maxFeePerUser, maxFeePerBlock string) sdk.Tx {
+	return TxSetSponsorshipLimits{
+		Contract:       contract,
+		MaxFeePerUser:  maxFeePerUser,
+		MaxFeePerBlock: maxFeePerBlock,
+	}.Wrap()
+}
+
+func (tx TxSetSponsorshipLimits) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxWithdrawSponsorship returns the unused sponsorship balance to the owner
+type TxWithdrawSponsorship struct {
+	Contract common.Address `json:"contract"`
+	Amount   string         `json:"amount"`
+}
+
+func (tx TxWithdrawSponsorship) ValidateBasic() error {
+	if amount, ok := parseAmount(tx.Amount); !ok || amount.Sign() == 0 {
+		return ErrBadAmount()
+	}
+	return nil
+}
+
+func NewTxWithdrawSponsorship(contract common.Address, amount string) sdk.Tx {
+	return TxWithdrawSponsorship{
+		Contract: contract,
+		Amount:   amount,
+	}.Wrap()
+}
+
+func (tx TxWithdrawSponsorship) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// parseAmount parses a non-negative amount in wei
+func parseAmount(s string) (*big.Int, bool) {
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, false
+	}
+	return amount, true
+}
+
+// validateLimits checks the fee limits, zero means no limit
+func validateLimits(maxFeePerUser, maxFeePerBlock string) error {
+	if _, ok := parseAmount(maxFeePerUser); !ok {
+		return ErrBadLimit()
+	}
+	if _, ok := parseAmount(maxFeePerBlock); !ok {
+		return ErrBadLimit()
+	}
+	return nil
+}
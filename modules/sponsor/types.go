@@ -0,0 +1,153 @@
+package sponsor
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+
+	sm "github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/utils"
+)
+
+// Sponsorship holds the balance a contract pays the gas of its callers with.
+// The amounts are in wei, a zero limit means no limit.
+type Sponsorship struct {
+	Contract       common.Address `json:"contract"`
+	Owner          common.Address `json:"owner"`
+	Balance        string         `json:"balance"`
+	MaxFeePerUser  string         `json:"max_fee_per_user"`
+	MaxFeePerBlock string         `json:"max_fee_per_block"`
+	TotalSponsored string         `json:"total_sponsored"`
+	LastHeight     int64          `json:"last_height"`    // height of the last sponsored tx
+	LastSponsored  string         `json:"last_sponsored"` // fee sponsored at the last height
+	CreatedAt      int64          `json:"created_at"`
+}
+
+// userUsage holds the fee sponsored for a user within the current window
+type userUsage struct {
+	WindowStart int64  `json:"window_start"`
+	Sponsored   string `json:"sponsored"`
+}
+
+func sponsorshipKey(contract common.Address) []byte {
+	return append(append([]byte{}, utils.SponsorshipKey...), contract.Bytes()...)
+}
+
+func userUsageKey(contract, user common.Address) []byte {
+	return append(sponsorshipKey(contract), user.Bytes()...)
+}
+
+func toBig(s string) *big.Int {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return i
+}
+
+// GetSponsorship returns the sponsorship of the contract, nil if it has not opted in
+func GetSponsorship(store sm.SimpleDB, contract common.Address) *Sponsorship {
+	b := store.Get(sponsorshipKey(contract))
+	if b == nil {
+		return nil
+	}
+
+	s := new(Sponsorship)
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil
+	}
+	return s
+}
+
+func saveSponsorship(store sm.SimpleDB, s *Sponsorship) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(sponsorshipKey(s.Contract), b)
+}
+
+// loadUserUsage returns the usage of the user, a new window is started once the current one elapsed
+func loadUserUsage(store sm.SimpleDB, contract, user common.Address, height int64) *userUsage {
+	blank := &userUsage{WindowStart: height, Sponsored: "0"}
+	b := store.Get(userUsageKey(contract, user))
+	if b == nil {
+		return blank
+	}
+
+	u := new(userUsage)
+	if err := json.Unmarshal(b, u); err != nil {
+		return blank
+	}
+	if height-u.WindowStart >= int64(utils.GetParams().SponsorshipUserWindow) {
+		return blank
+	}
+	return u
+}
+
+func saveUserUsage(store sm.SimpleDB, contract, user common.Address, u *userUsage) {
+	b, err := json.Marshal(u)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(userUsageKey(contract, user), b)
+}
+
+// CheckSponsoredTx checks the sponsorship of the contract can pay the fee of the tx sent by the user
+func CheckSponsoredTx(store sm.SimpleDB, contract, user common.Address, fee *big.Int, height int64) error {
+	s := GetSponsorship(store, contract)
+	if s == nil {
+		return ErrNoSponsorship()
+	}
+
+	if toBig(s.Balance).Cmp(fee) < 0 {
+		return ErrInsufficientSponsored()
+	}
+
+	if maxFee := toBig(s.MaxFeePerBlock); maxFee.Sign() > 0 {
+		sponsored := new(big.Int).Set(fee)
+		if s.LastHeight == height {
+			sponsored.Add(sponsored, toBig(s.LastSponsored))
+		}
+		if sponsored.Cmp(maxFee) > 0 {
+			return ErrBlockLimitReached()
+		}
+	}
+
+	if maxFee := toBig(s.MaxFeePerUser); maxFee.Sign() > 0 {
+		u := loadUserUsage(store, contract, user, height)
+		if new(big.Int).Add(toBig(u.Sponsored), fee).Cmp(maxFee) > 0 {
+			return ErrUserLimitReached()
+		}
+	}
+
+	return nil
+}
+
+// ChargeSponsoredTx deducts the fee of the tx from the sponsorship of the contract,
+// the fee is moved to the hold account where the gas fees are distributed from
+func ChargeSponsoredTx(store sm.SimpleDB, ethState *state.StateDB, contract, user common.Address, fee *big.Int, height int64) {
+	s := GetSponsorship(store, contract)
+	if s == nil {
+		return
+	}
+
+	s.Balance = new(big.Int).Sub(toBig(s.Balance), fee).String()
+	s.TotalSponsored = new(big.Int).Add(toBig(s.TotalSponsored), fee).String()
+	if s.LastHeight == height {
+		s.LastSponsored = new(big.Int).Add(toBig(s.LastSponsored), fee).String()
+	} else {
+		s.LastHeight = height
+		s.LastSponsored = fee.String()
+	}
+	saveSponsorship(store, s)
+
+	u := loadUserUsage(store, contract, user, height)
+	u.Sponsored = new(big.Int).Add(toBig(u.Sponsored), fee).String()
+	saveUserUsage(store, contract, user, u)
+
+	ethState.SubBalance(utils.SponsorAccount, fee)
+	ethState.AddBalance(utils.HoldAccount, fee)
+}
@@ -0,0 +1,61 @@
+package sponsor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/stretchr/testify/assert"
+
+	sm "github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/utils"
+)
+
+func TestSponsorshipLimits(t *testing.T) {
+	assert := assert.New(t)
+	utils.SetParams(utils.DefaultParams())
+
+	store := sm.NewMemKVStore()
+	ethState, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	contract := common.HexToAddress("0x01")
+	alice := common.HexToAddress("0x02")
+	bob := common.HexToAddress("0x03")
+	fee := big.NewInt(100)
+
+	assert.NotNil(CheckSponsoredTx(store, contract, alice, fee, 1))
+
+	saveSponsorship(store, &Sponsorship{
+		Contract:       contract,
+		Owner:          alice,
+		Balance:        "1000",
+		MaxFeePerUser:  "200",
+		MaxFeePerBlock: "300",
+		TotalSponsored: "0",
+		LastSponsored:  "0",
+	})
+
+	// per-user limit
+	assert.Nil(CheckSponsoredTx(store, contract, alice, fee, 1))
+	ChargeSponsoredTx(store, ethState, contract, alice, fee, 1)
+	ChargeSponsoredTx(store, ethState, contract, alice, fee, 1)
+	assert.NotNil(CheckSponsoredTx(store, contract, alice, fee, 1))
+
+	// per-block limit
+	assert.Nil(CheckSponsoredTx(store, contract, bob, fee, 1))
+	ChargeSponsoredTx(store, ethState, contract, bob, fee, 1)
+	assert.NotNil(CheckSponsoredTx(store, contract, bob, fee, 1))
+	assert.Nil(CheckSponsoredTx(store, contract, bob, fee, 2))
+
+	// the user window elapsed
+	window := int64(utils.GetParams().SponsorshipUserWindow)
+	assert.Nil(CheckSponsoredTx(store, contract, alice, fee, 1+window))
+
+	s := GetSponsorship(store, contract)
+	assert.Equal("700", s.Balance)
+	assert.Equal("300", s.TotalSponsored)
+
+	// balance
+	assert.NotNil(CheckSponsoredTx(store, contract, bob, big.NewInt(701), 2))
+}
@@ -3,8 +3,10 @@ package stake
 import (
 	"fmt"
 	"github.com/CyberMiles/travis/commons"
+	"github.com/CyberMiles/travis/metrics"
 	"github.com/CyberMiles/travis/sdk"
 	"github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/types"
 	"github.com/CyberMiles/travis/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/tendermint/tendermint/libs/log"
@@ -152,7 +154,12 @@ func (ad awardDistributor) Distribute() {
 		awardInfos = ad.distribute(backups, totalAward, totalBackupVotingPower, awardInfos)
 	}
 
-	commons.Transfer(utils.MintAccount, utils.HoldAccount, ad.getBlockAward())
+	blockAward := ad.getBlockAward()
+	commons.Transfer(utils.MintAccount, utils.HoldAccount, blockAward)
+	if metrics.Enabled() {
+		metrics.BlockAwardMinted.Add(metrics.ToCmt(blockAward.Int))
+		metrics.FeesDistributed.Add(metrics.ToCmt(ad.transactionFees.Int))
+	}
 
 	// reset block gas fee
 	utils.BlockGasFee.SetInt64(0)
@@ -213,6 +220,11 @@ func (ad *awardDistributor) distribute(vals []*simpleValidator, totalAward sdk.I
 		award := val.distributeAward(totalAward, totalVotingPower)
 		ai := AwardInfo{Address: val.ownerAddress, State: val.state, Amount: award.String()}
 		awardInfos = append(awardInfos, ai)
+
+		types.EventBus.Publish(types.EventAward, ad.height, []common.Address{val.ownerAddress}, map[string]interface{}{
+			"state":  val.state,
+			"amount": ai.Amount,
+		})
 	}
 	return awardInfos
 }
@@ -1,15 +1,23 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/CyberMiles/travis/modules/stake"
 	"github.com/CyberMiles/travis/sdk/client/commands"
+	"github.com/CyberMiles/travis/sdk/client/commands/query"
+	"github.com/CyberMiles/travis/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
-	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	"github.com/tendermint/go-amino"
 	"os"
+	"strconv"
 )
 
+var cdc = amino.NewCodec()
+
 /**
 The stake/query/validator is to query the current stake status of the validator. Not signed.
 
@@ -23,6 +31,12 @@ The stake/query/delegator is to query the current stake status of a delegator. N
 //nolint
 const (
 	FlagHeight = "height"
+	FlagLimit  = "limit"
+	FlagOffset = "offset"
+	FlagCursor = "cursor"
+	FlagState  = "state"
+
+	FlagValidator = "validator"
 )
 
 //nolint
@@ -45,11 +59,24 @@ var (
 		Short: "Query the current stake status of a delegator",
 	}
 
+	CmdQueryDelegations = &cobra.Command{
+		Use:   "delegations",
+		RunE:  cmdQueryDelegations,
+		Short: "Query the delegations of a validator, with the pending unstake requests of its delegators",
+		Long:  "Query the delegations of a validator, with the pending unstake requests of its delegators. Only the delegations in the state Y are returned unless --state is given.",
+	}
+
 	CmdQueryAwardInfo = &cobra.Command{
 		Use:   "award-info",
 		RunE:  cmdQueryAwardInfo,
 		Short: "Query the award info of a block",
 	}
+
+	CmdQueryValidatorSetChanges = &cobra.Command{
+		Use:   "validator-set-changes",
+		RunE:  cmdQueryValidatorSetChanges,
+		Short: "Query the validator set changes of a block, or the latest ones if no height given",
+	}
 )
 
 func init() {
@@ -57,16 +84,46 @@ func init() {
 	fsAddr := flag.NewFlagSet("", flag.ContinueOnError)
 	fsAddr.String(FlagAddress, "", "account address")
 
+	fsCursor := flag.NewFlagSet("", flag.ContinueOnError)
+	fsCursor.Int64(FlagLimit, 0, "Max number of results of the page, which needs --trust-node, 100 if not given")
+	fsCursor.Int64(FlagOffset, 0, "Number of results skipped before the page")
+	fsCursor.Int64(FlagCursor, 0, "Cursor of the page, the next one of the previous page")
+
+	fsPage := flag.NewFlagSet("", flag.ContinueOnError)
+	fsPage.AddFlagSet(fsCursor)
+	fsPage.String(FlagState, "", "Only the results in the state")
+
+	fsValidator := flag.NewFlagSet("", flag.ContinueOnError)
+	fsValidator.String(FlagValidator, "", "validator address")
+
 	CmdQueryValidator.Flags().AddFlagSet(fsAddr)
 	CmdQueryDelegator.Flags().AddFlagSet(fsAddr)
+	CmdQueryValidators.Flags().AddFlagSet(fsPage)
+	CmdQueryDelegator.Flags().AddFlagSet(fsPage)
+	CmdQueryDelegations.Flags().AddFlagSet(fsValidator)
+	CmdQueryDelegations.Flags().AddFlagSet(fsPage)
 }
 
 func cmdQueryValidators(cmd *cobra.Command, args []string) error {
-	b, err := Get("/validators", []byte{0})
-	if err != nil {
+	if page, ok := pageOf(cmd); ok {
+		filter := stake.CandidateFilter{State: viper.GetString(FlagState), Page: page}
+		var res stake.CandidatesPage
+		if err := getPage("/validators/page", filter, &res); err != nil {
+			return err
+		}
+		return foutputJSON(stake.NewCandidatesPageOutput(res))
+	}
+
+	b, err := query.Get("/validators", []byte{0}, query.VerifyRange(utils.CandidateKey))
+	if err != nil || len(b) == 0 {
+		return outputOrErr(b, err)
+	}
+
+	var candidates stake.Candidates
+	if err := json.Unmarshal(b, &candidates); err != nil {
 		return err
 	}
-	return Foutput(b)
+	return foutputJSON(stake.NewCandidatesOutput(candidates))
 }
 
 func cmdQueryValidator(cmd *cobra.Command, args []string) error {
@@ -75,49 +132,138 @@ func cmdQueryValidator(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("please enter validator address using --address")
 	}
 
-	b, err := Get("/validator", []byte(address))
-	if err != nil {
+	key := stake.CandidateKey(common.HexToAddress(address))
+	b, err := query.Get("/validator", []byte(address), query.VerifyKey(key))
+	if err != nil || len(b) == 0 {
+		return outputOrErr(b, err)
+	}
+
+	var candidate stake.Candidate
+	if err := json.Unmarshal(b, &candidate); err != nil {
 		return err
 	}
-	return Foutput(b)
+	return foutputJSON(stake.NewCandidateOutput(&candidate))
 }
 
 func cmdQueryDelegator(cmd *cobra.Command, args []string) error {
 	address := viper.GetString(FlagAddress)
-	b, err := Get("/delegator", []byte(address))
-	if err != nil {
+	if page, ok := pageOf(cmd); ok {
+		filter := stake.DelegationFilter{DelegatorAddress: address, State: viper.GetString(FlagState), Page: page}
+		var res stake.DelegationsPage
+		if err := getPage("/delegations/page", filter, &res); err != nil {
+			return err
+		}
+		return foutputJSON(stake.NewDelegationsPageOutput(res))
+	}
+
+	prefix := stake.DelegatorKeyPrefix(common.HexToAddress(address))
+	b, err := query.Get("/delegator", []byte(address), query.VerifyRange(prefix))
+	if err != nil || len(b) == 0 {
+		return outputOrErr(b, err)
+	}
+
+	var delegations []*stake.Delegation
+	if err := json.Unmarshal(b, &delegations); err != nil {
 		return err
 	}
-	return Foutput(b)
+	return foutputJSON(stake.NewDelegationsOutput(delegations))
+}
+
+// cmdQueryDelegations needs --trust-node, the delegations of a validator are
+// read from the database of the node
+func cmdQueryDelegations(cmd *cobra.Command, args []string) error {
+	address := viper.GetString(FlagValidator)
+	if address == "" {
+		return fmt.Errorf("please enter validator address using --validator")
+	}
+
+	page, _ := pageOf(cmd)
+	filter := stake.ValidatorDelegationsFilter{ValidatorAddress: address, State: viper.GetString(FlagState), Page: page}
+	var res stake.ValidatorDelegationsPage
+	if err := getPage("/validator/delegations", filter, &res); err != nil {
+		return err
+	}
+	if res.Validator == nil {
+		return fmt.Errorf("no validator of address %s", address)
+	}
+	return foutputJSON(stake.NewValidatorDelegationsPageOutput(&res))
 }
 
 func cmdQueryAwardInfo(cmd *cobra.Command, args []string) error {
-	b, err := GetByHeight("/awardInfo", []byte{0x00}, int64(viper.GetInt(FlagHeight)))
+	b, err := query.Get("/awardInfo", []byte{0x00}, query.VerifyKey(utils.AwardInfosKey))
 	if err != nil {
 		return err
 	}
-	return Foutput(b)
-}
+	if viper.GetBool(commands.FlagTrustNode) || len(b) == 0 {
+		return Foutput(b)
+	}
 
-func Get(path string, params []byte) ([]byte, error) {
-	node := commands.GetNode()
-	resp, err := node.ABCIQuery(path, params)
-	if resp == nil {
-		return nil, err
+	// the proven value is the one of the store
+	var awardInfos stake.AwardInfos
+	if err := cdc.UnmarshalBinary(b, &awardInfos); err != nil {
+		return err
 	}
-	return resp.Response.Value, err
+	b, err = json.Marshal(awardInfos)
+	if err != nil {
+		return err
+	}
+	return Foutput(b)
 }
 
-func GetByHeight(path string, params []byte, height int64) ([]byte, error) {
-	node := commands.GetNode()
-	resp, err := node.ABCIQueryWithOptions(path, params, rpcclient.ABCIQueryOptions{Trusted: true, Height: int64(height)})
-	if resp == nil {
-		return nil, err
+func cmdQueryValidatorSetChanges(cmd *cobra.Command, args []string) error {
+	height := viper.GetInt64(FlagHeight)
+	b, err := query.GetUnprovable("/validatorSetChanges", []byte(strconv.FormatInt(height, 10)))
+	if err != nil {
+		return err
 	}
-	return resp.Response.Value, err
+	return Foutput(b)
 }
 
 func Foutput(b []byte) error {
 	_, err := fmt.Fprintf(os.Stdout, "%s\n", b)
 	return err
 }
+
+// foutputJSON prints the candidates and the delegations with their amounts in cmt
+func foutputJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return Foutput(b)
+}
+
+// pageOf returns the page of the flags, if any of them is given
+func pageOf(cmd *cobra.Command) (stake.Page, bool) {
+	paged := false
+	for _, name := range []string{FlagLimit, FlagOffset, FlagCursor, FlagState} {
+		paged = paged || cmd.Flags().Changed(name)
+	}
+	page := stake.Page{
+		Cursor: viper.GetInt64(FlagCursor),
+		Offset: viper.GetInt64(FlagOffset),
+		Limit:  viper.GetInt64(FlagLimit),
+	}
+	return page, paged
+}
+
+// getPage queries a page, the pages are read from the database of the node
+// and can't be proven
+func getPage(path string, filter interface{}, res interface{}) error {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return err
+	}
+	b, err := query.GetUnprovable(path, data)
+	if err != nil || len(b) == 0 {
+		return err
+	}
+	return json.Unmarshal(b, res)
+}
+
+func outputOrErr(b []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	return Foutput(b)
+}
@@ -3,7 +3,6 @@ package commands
 import (
 	"fmt"
 	"github.com/CyberMiles/travis/utils"
-	"math/big"
 
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
@@ -128,10 +127,10 @@ func init() {
 	fsPk.String(FlagPubKey, "", "PubKey of the validator-candidate")
 
 	fsAmount := flag.NewFlagSet("", flag.ContinueOnError)
-	fsAmount.String(FlagAmount, "", "Amount of CMTs")
+	fsAmount.String(FlagAmount, "", "Amount of CMTs, as 1000cmt, 1.5cmt or 500wei")
 
 	fsCandidate := flag.NewFlagSet("", flag.ContinueOnError)
-	fsCandidate.String(FlagMaxAmount, "", "Max amount of CMTs to be staked")
+	fsCandidate.String(FlagMaxAmount, "", "Max amount of CMTs to be staked, as 1000cmt, 1.5cmt or 500wei")
 	fsCandidate.String(FlagName, "", "name")
 	fsCandidate.String(FlagWebsite, "", "website")
 	fsCandidate.String(FlagLocation, "", "location")
@@ -202,11 +201,9 @@ func cmdDeclareCandidacy(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	maxAmount := viper.GetString(FlagMaxAmount)
-	v := new(big.Int)
-	_, ok := v.SetString(maxAmount, 10)
-	if !ok || v.Cmp(big.NewInt(0)) <= 0 {
-		return fmt.Errorf("max-amount must be positive interger")
+	maxAmount, err := parseAmount(FlagMaxAmount)
+	if err != nil {
+		return err
 	}
 
 	c, ok := sdk.NewRatFromString(viper.GetString(FlagCompRate))
@@ -236,13 +233,13 @@ func cmdUpdateCandidacy(cmd *cobra.Command, args []string) error {
 		pk = tmp
 	}
 
-	maxAmount := viper.GetString(FlagMaxAmount)
-	if !utils.IsBlank(maxAmount) {
-		v := new(big.Int)
-		_, ok := v.SetString(maxAmount, 10)
-		if !ok || v.Cmp(big.NewInt(0)) <= 0 {
-			return fmt.Errorf("max-amount must be positive interger")
+	maxAmount := ""
+	if !utils.IsBlank(viper.GetString(FlagMaxAmount)) {
+		tmp, err := parseAmount(FlagMaxAmount)
+		if err != nil {
+			return err
 		}
+		maxAmount = tmp
 	}
 
 	cr := sdk.Rat{}
@@ -298,11 +295,9 @@ func cmdDeactivateCandidacy(cmd *cobra.Command, args []string) error {
 }
 
 func cmdDelegate(cmd *cobra.Command, args []string) error {
-	amount := viper.GetString(FlagAmount)
-	v := new(big.Int)
-	_, ok := v.SetString(amount, 10)
-	if !ok || v.Cmp(big.NewInt(0)) <= 0 {
-		return fmt.Errorf("amount must be positive interger")
+	amount, err := parseAmount(FlagAmount)
+	if err != nil {
+		return err
 	}
 
 	validatorAddress := common.HexToAddress(viper.GetString(FlagCandidateAddress))
@@ -330,11 +325,9 @@ func cmdWithdraw(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("please enter validator address using --validator-address")
 	}
 
-	amount := viper.GetString(FlagAmount)
-	v := new(big.Int)
-	_, ok := v.SetString(amount, 10)
-	if !ok || v.Cmp(big.NewInt(0)) <= 0 {
-		return fmt.Errorf("amount must be positive interger")
+	amount, err := parseAmount(FlagAmount)
+	if err != nil {
+		return err
 	}
 
 	completelyWithdraw := viper.GetBool(FlagCompletelyWithdraw)
@@ -376,3 +369,15 @@ func cmdAcceptCandidacyAccountUpdate(cmd *cobra.Command, args []string) error {
 	tx := stake.NewTxAcceptCandidacyAccountUpdate(updateAccountRequestId)
 	return txcmd.DoTx(tx)
 }
+
+// parseAmount returns the amount of wei of the flag, given as 1000cmt, 1.5cmt or 500wei
+func parseAmount(name string) (string, error) {
+	coin, err := sdk.ParseCoin(viper.GetString(name))
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", name, err)
+	}
+	if !coin.IsPositive() {
+		return "", fmt.Errorf("%s must be positive", name)
+	}
+	return coin.Wei(), nil
+}
@@ -3,6 +3,7 @@ package stake
 import (
 	"database/sql"
 	"fmt"
+	"math/big"
 
 	"github.com/CyberMiles/travis/sdk"
 	"github.com/CyberMiles/travis/sdk/dbm"
@@ -216,6 +217,7 @@ func composeCandidateResults(rows *sql.Rows) (candidates Candidates) {
 func SaveCandidate(candidate *Candidate) {
 	txWrapper := getSqlTxWrapper()
 	defer txWrapper.Commit()
+	candidateMirror.Touch(CandidateKey(common.HexToAddress(candidate.OwnerAddress)))
 
 	stmt, err := txWrapper.tx.Prepare("insert into candidates(pub_key, address, shares, voting_power, pending_voting_power, max_shares, comp_rate, name, website, location, profile, email, verified, active, hash, block_height, rank, state, num_of_delegators, created_at) values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
@@ -254,6 +256,7 @@ func SaveCandidate(candidate *Candidate) {
 func updateCandidate(candidate *Candidate) {
 	txWrapper := getSqlTxWrapper()
 	defer txWrapper.Commit()
+	touchCandidate(txWrapper.tx, candidate)
 
 	stmt, err := txWrapper.tx.Prepare("update candidates set address = ?, shares = ?, voting_power = ?, pending_voting_power = ?, max_shares = ?, comp_rate = ?, name =?, website = ?, location = ?, profile = ?, email = ?, verified = ?, active = ?, hash = ?, rank = ?, state = ?, num_of_delegators = ?, pub_key = ? where id = ?")
 	if err != nil {
@@ -292,6 +295,19 @@ func cleanCandidates() {
 	txWrapper := getSqlTxWrapper()
 	defer txWrapper.Commit()
 
+	rows, err := txWrapper.tx.Query("select address from candidates where shares = ? and active <> ?", "0", "N")
+	if err != nil {
+		panic(err)
+	}
+	for rows.Next() {
+		var address string
+		if err := rows.Scan(&address); err != nil {
+			panic(err)
+		}
+		candidateMirror.Touch(CandidateKey(common.HexToAddress(address)))
+	}
+	rows.Close()
+
 	stmt, err := txWrapper.tx.Prepare("update candidates set active = ? where shares = ?")
 	if err != nil {
 		panic(err)
@@ -336,6 +352,7 @@ func GetCandidatesTotalShares() (res sdk.Int) {
 func SaveDelegation(d *Delegation) {
 	txWrapper := getSqlTxWrapper()
 	defer txWrapper.Commit()
+	delegationMirror.Touch(DelegationKey(d.DelegatorAddress, d.CandidateId))
 
 	stmt, err := txWrapper.tx.Prepare("insert into delegations(delegator_address, candidate_id, delegate_amount, award_amount, withdraw_amount, pending_withdraw_amount, slash_amount, comp_rate, hash, voting_power, state, block_height, average_staking_date, created_at, source, completely_withdraw) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
@@ -353,6 +370,7 @@ func SaveDelegation(d *Delegation) {
 func RemoveDelegation(id int64) {
 	txWrapper := getSqlTxWrapper()
 	defer txWrapper.Commit()
+	touchDelegation(txWrapper.tx, id)
 
 	stmt, err := txWrapper.tx.Prepare("update delegations set state = ? where id = ?")
 	if err != nil {
@@ -370,6 +388,8 @@ func RemoveDelegation(id int64) {
 func UpdateDelegation(d *Delegation) {
 	txWrapper := getSqlTxWrapper()
 	defer txWrapper.Commit()
+	touchDelegation(txWrapper.tx, d.Id)
+	delegationMirror.Touch(DelegationKey(d.DelegatorAddress, d.CandidateId))
 	stmt, err := txWrapper.tx.Prepare("update delegations set delegator_address = ?, delegate_amount = ?, award_amount =?, withdraw_amount = ?, pending_withdraw_amount = ?, slash_amount = ?, comp_rate = ?, hash = ?, voting_power = ?, state = ?, average_staking_date = ?, source = ?, completely_withdraw = ? where id = ?")
 	if err != nil {
 		panic(err)
@@ -529,6 +549,29 @@ func saveSlash(slash *Slash) {
 	}
 }
 
+// getSlashedCandidates returns the ids of the candidates slashed after the validator set
+// was updated at fromHeight, up to toHeight
+func getSlashedCandidates(fromHeight, toHeight int64) map[int64]struct{} {
+	txWrapper := getSqlTxWrapper()
+	defer txWrapper.Commit()
+
+	rows, err := txWrapper.tx.Query("select distinct candidate_id from slashes where (block_height > ? and block_height <= ?) or (block_height = ? and reason = ?)", fromHeight, toHeight, fromHeight, badProposerReason)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	slashed := make(map[int64]struct{})
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			panic(err)
+		}
+		slashed[id] = struct{}{}
+	}
+	return slashed
+}
+
 func saveUnstakeRequest(req *UnstakeRequest) {
 	txWrapper := getSqlTxWrapper()
 	defer txWrapper.Commit()
@@ -620,6 +663,19 @@ func GetUnstakeRequestsByDelegator(delegatorAddress common.Address) []*UnstakeRe
 	return getUnstakeRequestsInternal(cond)
 }
 
+// GetPendingUnstakeAmount returns the wei of all the pending unstake requests
+func GetPendingUnstakeAmount() *big.Int {
+	cond := make(map[string]interface{})
+	cond["state"] = "PENDING"
+	total := big.NewInt(0)
+	for _, req := range getUnstakeRequestsInternal(cond) {
+		if amount, ok := new(big.Int).SetString(req.Amount, 10); ok {
+			total.Add(total, amount)
+		}
+	}
+	return total
+}
+
 func updateUnstakeRequest(req *UnstakeRequest) {
 	txWrapper := getSqlTxWrapper()
 	defer txWrapper.Commit()
@@ -754,3 +810,61 @@ func updateCandidateAccountUpdateRequest(req *CandidateAccountUpdateRequest) {
 		panic(err)
 	}
 }
+
+func saveValidatorSetChange(change *ValidatorSetChange) {
+	txWrapper := getSqlTxWrapper()
+	defer txWrapper.Commit()
+
+	stmt, err := txWrapper.tx.Prepare("insert into validator_set_changes(candidate_id, address, old_state, new_state, old_rank, new_rank, old_tendermint_voting_power, new_tendermint_voting_power, reason, block_height) values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		panic(err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(
+		change.CandidateId,
+		change.Address.String(),
+		change.OldState,
+		change.NewState,
+		change.OldRank,
+		change.NewRank,
+		change.OldTendermintVotingPower,
+		change.NewTendermintVotingPower,
+		change.Reason,
+		change.BlockHeight,
+	)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func composeValidatorSetChangeResults(rows *sql.Rows) (changes ValidatorSetChanges) {
+	for rows.Next() {
+		var address, oldState, newState, reason string
+		var id, candidateId, oldRank, newRank, oldTvp, newTvp, blockHeight int64
+		err := rows.Scan(&id, &candidateId, &address, &oldState, &newState, &oldRank, &newRank, &oldTvp, &newTvp, &reason, &blockHeight)
+		if err != nil {
+			panic(err)
+		}
+
+		change := &ValidatorSetChange{
+			Id:                       id,
+			CandidateId:              candidateId,
+			Address:                  common.HexToAddress(address),
+			OldState:                 oldState,
+			NewState:                 newState,
+			OldRank:                  oldRank,
+			NewRank:                  newRank,
+			OldTendermintVotingPower: oldTvp,
+			NewTendermintVotingPower: newTvp,
+			Reason:                   reason,
+			BlockHeight:              blockHeight,
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		// panic(err)
+	}
+	return
+}
@@ -29,6 +29,7 @@ var (
 	errBadRequest                         = fmt.Errorf("Bad request")
 	errCandidateAlreadyWithdrew           = fmt.Errorf("Candidate has been withdrawn")
 	errDelegatorHasPendingWithdrawal      = fmt.Errorf("Delegator has a pending withdrawal")
+	errBelowMinStakingAmount              = fmt.Errorf("Amount is below the min staking amount")
 
 	invalidInput = errors.CodeTypeBaseInvalidInput
 )
@@ -110,3 +111,7 @@ func ErrCandidateAlreadyWithdrew() error {
 func ErrDelegatorHasPendingWithdrawal() error {
 	return errors.WithCode(errDelegatorHasPendingWithdrawal, errors.CodeTypeBaseInvalidOutput)
 }
+
+func ErrBelowMinStakingAmount() error {
+	return errors.WithCode(errBelowMinStakingAmount, errors.CodeTypeBaseInvalidOutput)
+}
@@ -0,0 +1,204 @@
+package stake
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	sm "github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/types"
+)
+
+// ExportGenesis adds the stake of the chain at the height to the genesis, the staked
+// coins stay in the hold account of the exported vm state. The inactive candidates
+// are exported too while they hold delegations or pending unstake requests.
+func ExportGenesis(store sm.SimpleDB, genDoc *types.GenesisDoc, height int64) {
+	delegations := GetDelegations("Y")
+	cond := make(map[string]interface{})
+	cond["state"] = "PENDING"
+	unstakeRequests := getUnstakeRequestsInternal(cond)
+
+	staked := make(map[int64]bool)
+	for _, d := range delegations {
+		staked[d.CandidateId] = true
+	}
+	for _, r := range unstakeRequests {
+		staked[r.CandidateId] = true
+	}
+
+	owners := make(map[int64]string)
+	genDoc.Validators = nil
+	for _, c := range GetCandidates() {
+		if c.Active != "Y" && !staked[c.Id] {
+			continue
+		}
+		owners[c.Id] = c.OwnerAddress
+		genDoc.Candidates = append(genDoc.Candidates, types.GenesisCandidate{
+			PubKey:             c.PubKey,
+			OwnerAddress:       c.OwnerAddress,
+			Shares:             c.Shares,
+			VotingPower:        c.VotingPower,
+			PendingVotingPower: c.PendingVotingPower,
+			MaxShares:          c.MaxShares,
+			CompRate:           c.CompRate,
+			Name:               c.Description.Name,
+			Website:            c.Description.Website,
+			Location:           c.Description.Location,
+			Email:              c.Description.Email,
+			Profile:            c.Description.Profile,
+			Verified:           c.Verified,
+			Active:             c.Active,
+			Rank:               c.Rank,
+			State:              c.State,
+			NumOfDelegators:    c.NumOfDelegators,
+			CreatedAt:          c.CreatedAt,
+		})
+
+		if c.Active == "Y" && c.State == "Validator" {
+			genDoc.Validators = append(genDoc.Validators, types.GenesisValidator{
+				PubKey:   c.PubKey,
+				Power:    "10",
+				Name:     c.Description.Name,
+				Address:  c.OwnerAddress,
+				CompRate: c.CompRate,
+				Website:  c.Description.Website,
+				Location: c.Description.Location,
+				Email:    c.Description.Email,
+				Profile:  c.Description.Profile,
+			})
+		}
+	}
+
+	for _, d := range delegations {
+		owner, ok := owners[d.CandidateId]
+		if !ok {
+			continue
+		}
+		genDoc.Delegations = append(genDoc.Delegations, types.GenesisDelegation{
+			DelegatorAddress:      d.DelegatorAddress.String(),
+			ValidatorAddress:      owner,
+			DelegateAmount:        d.DelegateAmount,
+			AwardAmount:           d.AwardAmount,
+			WithdrawAmount:        d.WithdrawAmount,
+			PendingWithdrawAmount: d.PendingWithdrawAmount,
+			SlashAmount:           d.SlashAmount,
+			CompRate:              d.CompRate,
+			VotingPower:           d.VotingPower,
+			AverageStakingDate:    d.AverageStakingDate,
+			Source:                d.Source,
+			CompletelyWithdraw:    d.CompletelyWithdraw,
+			CreatedAt:             d.CreatedAt,
+		})
+	}
+
+	for _, r := range unstakeRequests {
+		owner, ok := owners[r.CandidateId]
+		if !ok {
+			continue
+		}
+		performed := r.PerformedBlockHeight - height
+		if performed < 1 {
+			performed = 1
+		}
+		genDoc.UnstakeRequests = append(genDoc.UnstakeRequests, types.GenesisUnstakeRequest{
+			DelegatorAddress:     r.DelegatorAddress.String(),
+			ValidatorAddress:     owner,
+			PerformedBlockHeight: performed,
+			Amount:               r.Amount,
+		})
+	}
+
+	for pk, a := range LoadAbsentValidators(store).Validators {
+		genDoc.AbsentValidators = append(genDoc.AbsentValidators, types.GenesisAbsence{
+			PubKey:          pk,
+			Count:           a.Count,
+			LastBlockHeight: a.LastBlockHeight - height,
+		})
+	}
+}
+
+// ImportGenesis restores the stake exported from a running chain
+func ImportGenesis(store sm.SimpleDB, genDoc *types.GenesisDoc) error {
+	ids := make(map[string]int64)
+	for _, gc := range genDoc.Candidates {
+		owner := common.HexToAddress(gc.OwnerAddress)
+		// the genesis exported without the flag only has active candidates
+		active := gc.Active
+		if active == "" {
+			active = "Y"
+		}
+		if GetCandidateByAddress(owner) != nil {
+			return ErrCandidateExistsAddr()
+		}
+		SaveCandidate(&Candidate{
+			PubKey:             gc.PubKey,
+			OwnerAddress:       owner.String(),
+			Shares:             gc.Shares,
+			VotingPower:        gc.VotingPower,
+			PendingVotingPower: gc.PendingVotingPower,
+			MaxShares:          gc.MaxShares,
+			CompRate:           gc.CompRate,
+			CreatedAt:          gc.CreatedAt,
+			Description: Description{
+				Name:     gc.Name,
+				Website:  gc.Website,
+				Location: gc.Location,
+				Email:    gc.Email,
+				Profile:  gc.Profile,
+			},
+			Verified:        gc.Verified,
+			Active:          active,
+			Rank:            gc.Rank,
+			State:           gc.State,
+			NumOfDelegators: gc.NumOfDelegators,
+		})
+		ids[owner.String()] = GetCandidateByAddress(owner).Id
+	}
+
+	for _, gd := range genDoc.Delegations {
+		id, ok := ids[common.HexToAddress(gd.ValidatorAddress).String()]
+		if !ok {
+			return fmt.Errorf("the delegation of %s is to an unknown candidate %s", gd.DelegatorAddress, gd.ValidatorAddress)
+		}
+		SaveDelegation(&Delegation{
+			DelegatorAddress:      common.HexToAddress(gd.DelegatorAddress),
+			CandidateId:           id,
+			DelegateAmount:        gd.DelegateAmount,
+			AwardAmount:           gd.AwardAmount,
+			WithdrawAmount:        gd.WithdrawAmount,
+			PendingWithdrawAmount: gd.PendingWithdrawAmount,
+			SlashAmount:           gd.SlashAmount,
+			CompRate:              gd.CompRate,
+			VotingPower:           gd.VotingPower,
+			State:                 "Y",
+			AverageStakingDate:    gd.AverageStakingDate,
+			CreatedAt:             gd.CreatedAt,
+			Source:                gd.Source,
+			CompletelyWithdraw:    gd.CompletelyWithdraw,
+		})
+	}
+
+	for _, gr := range genDoc.UnstakeRequests {
+		id, ok := ids[common.HexToAddress(gr.ValidatorAddress).String()]
+		if !ok {
+			return fmt.Errorf("the unstake request of %s is to an unknown candidate %s", gr.DelegatorAddress, gr.ValidatorAddress)
+		}
+		saveUnstakeRequest(&UnstakeRequest{
+			DelegatorAddress:     common.HexToAddress(gr.DelegatorAddress),
+			CandidateId:          id,
+			PerformedBlockHeight: gr.PerformedBlockHeight,
+			Amount:               gr.Amount,
+			State:                "PENDING",
+			ActualAmount:         "0",
+		})
+	}
+
+	if len(genDoc.AbsentValidators) > 0 {
+		absentValidators := &AbsentValidators{Validators: make(map[string]*Absence)}
+		for _, ga := range genDoc.AbsentValidators {
+			absentValidators.Validators[ga.PubKey] = &Absence{Count: ga.Count, LastBlockHeight: ga.LastBlockHeight}
+		}
+		SaveAbsentValidators(store, absentValidators)
+	}
+	return nil
+}
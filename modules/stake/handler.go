@@ -64,6 +64,43 @@ func SetGenesisValidator(val types.GenesisValidator, store state.SimpleDB) error
 	return deliverer.declareGenesisCandidacy(tx, val)
 }
 
+// SetGenesisDelegation delegates the coins of a genesis account to a genesis validator
+func SetGenesisDelegation(gd types.GenesisDelegation, store state.SimpleDB) error {
+	validator := common.HexToAddress(gd.ValidatorAddress)
+	candidate := GetCandidateByAddress(validator)
+	if candidate == nil {
+		return ErrBadValidatorAddr()
+	}
+
+	// the delegation is capped as a delegate tx, the transfer checks the balance
+	amount, ok := sdk.NewIntFromString(gd.DelegateAmount)
+	if !ok || amount.LTE(sdk.ZeroInt) {
+		return ErrBadAmount()
+	}
+	if amount.LT(sdk.NewInt(utils.GetParams().MinStakingAmount).Mul(sdk.E18Int)) {
+		return ErrBelowMinStakingAmount()
+	}
+	if candidate.ParseShares().Add(amount).GT(candidate.ParseMaxShares()) {
+		return ErrReachMaxAmount()
+	}
+
+	deliverer := deliver{
+		store:  store,
+		sender: common.HexToAddress(gd.DelegatorAddress),
+		params: utils.GetParams(),
+		ctx:    types.NewContext("", 0, 0, nil),
+	}
+	txDelegate := TxDelegate{ValidatorAddress: validator, Amount: gd.DelegateAmount}
+	if err := deliverer.delegate(txDelegate); err != nil {
+		return err
+	}
+
+	candidate = GetCandidateByAddress(validator) // candidate object was modified by the delegation operation.
+	candidate.PendingVotingPower = candidate.CalcVotingPower(0)
+	updateCandidate(candidate)
+	return nil
+}
+
 // CheckTx checks if the tx is properly structured
 func CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
 	err = tx.ValidateBasic()
@@ -87,10 +124,16 @@ func CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckR
 
 	switch txInner := tx.Unwrap().(type) {
 	case TxDeclareCandidacy:
-		gasFee := utils.CalGasFee(params.DeclareCandidacyGas, params.GasPrice)
+		gasFee, err := ctx.GasFee(params.DeclareCandidacyGas)
+		if err != nil {
+			return res, err
+		}
 		return res, checker.declareCandidacy(txInner, gasFee)
 	case TxUpdateCandidacy:
-		gasFee := utils.CalGasFee(params.UpdateCandidacyGas, params.GasPrice)
+		gasFee, err := ctx.GasFee(params.UpdateCandidacyGas)
+		if err != nil {
+			return res, err
+		}
 		return res, checker.updateCandidacy(txInner, gasFee)
 	case TxWithdrawCandidacy:
 		return res, checker.withdrawCandidacy(txInner)
@@ -105,14 +148,23 @@ func CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckR
 	case TxWithdraw:
 		return res, checker.withdraw(txInner)
 	case TxSetCompRate:
-		gasFee := utils.CalGasFee(params.SetCompRateGas, params.GasPrice)
+		gasFee, err := ctx.GasFee(params.SetCompRateGas)
+		if err != nil {
+			return res, err
+		}
 		return res, checker.setCompRate(txInner, gasFee)
 	case TxUpdateCandidacyAccount:
-		gasFee := utils.CalGasFee(params.UpdateCandidateAccountGas, params.GasPrice)
-		_, err := checker.updateCandidateAccount(txInner, gasFee)
+		gasFee, err := ctx.GasFee(params.UpdateCandidateAccountGas)
+		if err != nil {
+			return res, err
+		}
+		_, err = checker.updateCandidateAccount(txInner, gasFee)
 		return res, err
 	case TxAcceptCandidacyAccountUpdate:
-		gasFee := utils.CalGasFee(params.AcceptCandidateAccountUpdateRequestGas, params.GasPrice)
+		gasFee, err := ctx.GasFee(params.AcceptCandidateAccountUpdateRequestGas)
+		if err != nil {
+			return res, err
+		}
 		return res, checker.acceptCandidateAccountUpdateRequest(txInner, gasFee)
 	}
 
@@ -140,10 +192,10 @@ func DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx, hash []byte)
 	}
 	res.GasFee = big.NewInt(0)
 
-	// Run the transaction
+	// Run the transaction, the declared gas has been validated by CheckTx
 	switch txInner := tx.Unwrap().(type) {
 	case TxDeclareCandidacy:
-		gasFee := utils.CalGasFee(params.DeclareCandidacyGas, params.GasPrice)
+		gasFee, _ := ctx.GasFee(params.DeclareCandidacyGas)
 		err := deliverer.declareCandidacy(txInner, gasFee)
 		if err == nil {
 			res.GasUsed = int64(params.DeclareCandidacyGas)
@@ -151,7 +203,7 @@ func DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx, hash []byte)
 		}
 		return res, err
 	case TxUpdateCandidacy:
-		gasFee := utils.CalGasFee(params.UpdateCandidacyGas, params.GasPrice)
+		gasFee, _ := ctx.GasFee(params.UpdateCandidacyGas)
 		err := deliverer.updateCandidacy(txInner, gasFee)
 		if err == nil {
 			res.GasUsed = int64(params.UpdateCandidacyGas)
@@ -171,7 +223,7 @@ func DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx, hash []byte)
 	case TxWithdraw:
 		return res, deliverer.withdraw(txInner)
 	case TxSetCompRate:
-		gasFee := utils.CalGasFee(params.SetCompRateGas, params.GasPrice)
+		gasFee, _ := ctx.GasFee(params.SetCompRateGas)
 		err := deliverer.setCompRate(txInner, gasFee)
 		if err == nil {
 			res.GasUsed = int64(params.SetCompRateGas)
@@ -179,7 +231,7 @@ func DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx, hash []byte)
 		}
 		return res, err
 	case TxUpdateCandidacyAccount:
-		gasFee := utils.CalGasFee(params.UpdateCandidateAccountGas, params.GasPrice)
+		gasFee, _ := ctx.GasFee(params.UpdateCandidateAccountGas)
 		id, err := deliverer.updateCandidateAccount(txInner, gasFee)
 		if err == nil {
 			res.GasUsed = int64(params.UpdateCandidateAccountGas)
@@ -188,7 +240,7 @@ func DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx, hash []byte)
 		res.Data = []byte(strconv.Itoa(int(id)))
 		return res, err
 	case TxAcceptCandidacyAccountUpdate:
-		gasFee := utils.CalGasFee(params.AcceptCandidateAccountUpdateRequestGas, params.GasPrice)
+		gasFee, _ := ctx.GasFee(params.AcceptCandidateAccountUpdateRequestGas)
 		err := deliverer.acceptCandidateAccountUpdateRequest(txInner, gasFee)
 		if err == nil {
 			res.GasUsed = int64(params.AcceptCandidateAccountUpdateRequestGas)
@@ -774,6 +826,7 @@ func (d deliver) withdrawCandidacy(tx TxWithdrawCandidacy) error {
 	candidate.Shares = "0"
 	candidate.NumOfDelegators = 0
 	updateCandidate(candidate)
+	publishCandidateStateChanged(d.ctx.BlockHeight(), candidate, candidate.State, "withdraw")
 	return nil
 }
 
@@ -796,8 +849,12 @@ func (d deliver) activateCandidacy(tx TxActivateCandidacy) error {
 		return ErrBadValidatorAddr()
 	}
 
+	oldActive := candidate.Active
 	candidate.Active = "Y"
 	updateCandidate(candidate)
+	if oldActive != candidate.Active {
+		publishCandidateStateChanged(d.ctx.BlockHeight(), candidate, candidate.State, "activate")
+	}
 	return nil
 }
 
@@ -808,8 +865,12 @@ func (d deliver) deactivateCandidacy(tx TxDeactivateCandidacy) error {
 		return ErrBadValidatorAddr()
 	}
 
+	oldActive := candidate.Active
 	candidate.Active = "N"
 	updateCandidate(candidate)
+	if oldActive != candidate.Active {
+		publishCandidateStateChanged(d.ctx.BlockHeight(), candidate, candidate.State, "deactivate")
+	}
 	return nil
 }
 
@@ -1077,6 +1138,12 @@ func HandlePendingUnstakeRequests(height int64) error {
 
 		// transfer coins back to account
 		commons.Transfer(utils.HoldAccount, req.DelegatorAddress, amount)
+
+		types.EventBus.Publish(types.EventUnstakeCompleted, height, []common.Address{req.DelegatorAddress, common.HexToAddress(candidate.OwnerAddress)}, map[string]interface{}{
+			"requestId":   req.Id,
+			"candidateId": candidate.Id,
+			"amount":      amount.String(),
+		})
 	}
 
 	return nil
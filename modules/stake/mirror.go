@@ -0,0 +1,143 @@
+package stake
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/types"
+	"github.com/CyberMiles/travis/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	candidateMirror  = state.NewMirror(utils.CandidateKey)
+	delegationMirror = state.NewMirror(utils.DelegationKey)
+)
+
+// CandidateKey is the key of the candidate in the iavl store
+func CandidateKey(address common.Address) []byte {
+	return append(append([]byte{}, utils.CandidateKey...), address.Bytes()...)
+}
+
+// DelegatorKeyPrefix is the key prefix of the delegations of the delegator
+func DelegatorKeyPrefix(delegatorAddress common.Address) []byte {
+	return append(append([]byte{}, utils.DelegationKey...), delegatorAddress.Bytes()...)
+}
+
+// DelegationKey is the key of the delegation in the iavl store
+func DelegationKey(delegatorAddress common.Address, candidateId int64) []byte {
+	id := make([]byte, 8)
+	binary.BigEndian.PutUint64(id, uint64(candidateId))
+	return append(DelegatorKeyPrefix(delegatorAddress), id...)
+}
+
+// MirrorState copies the candidates and the delegations changed in the block
+// to the iavl store, so they can be proven to the light clients. All of them
+// are copied when the mirror is seeded.
+func MirrorState(store state.SimpleDB, seed bool) {
+	if !seed {
+		candidateMirror.Update(store, loadMirroredCandidate)
+		delegationMirror.Update(store, loadMirroredDelegation)
+		return
+	}
+
+	candidates := GetCandidates()
+	byId := make(map[int64]*Candidate)
+	values := make(map[string][]byte)
+	for _, c := range candidates {
+		byId[c.Id] = c
+		b, _ := json.Marshal(c)
+		values[string(CandidateKey(common.HexToAddress(c.OwnerAddress)))] = b
+	}
+	candidateMirror.Seed(store, values)
+
+	values = make(map[string][]byte)
+	for _, d := range getDelegationsInternal(make(map[string]interface{})) {
+		// as the delegator query returns them
+		if c, ok := byId[d.CandidateId]; ok {
+			d.ValidatorAddress = c.OwnerAddress
+			d.PubKey = c.PubKey
+		}
+		b, _ := json.Marshal(d)
+		values[string(DelegationKey(d.DelegatorAddress, d.CandidateId))] = b
+	}
+	delegationMirror.Seed(store, values)
+}
+
+// loadMirroredCandidate returns the value of the candidate key, the last row wins as in the seed
+func loadMirroredCandidate(key []byte) []byte {
+	address := common.BytesToAddress(key[len(utils.CandidateKey):])
+	candidates := getCandidatesInternal(map[string]interface{}{"address": address.String()})
+	if len(candidates) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(candidates[len(candidates)-1])
+	return b
+}
+
+// loadMirroredDelegation returns the value of the delegation key, the last row wins as in the seed
+func loadMirroredDelegation(key []byte) []byte {
+	key = key[len(utils.DelegationKey):]
+	delegatorAddress := common.BytesToAddress(key[:common.AddressLength])
+	candidateId := int64(binary.BigEndian.Uint64(key[common.AddressLength:]))
+	delegations := getDelegationsInternal(map[string]interface{}{
+		"delegator_address": delegatorAddress.String(),
+		"candidate_id":      candidateId,
+	})
+	if len(delegations) == 0 {
+		return nil
+	}
+	d := delegations[len(delegations)-1]
+	if c := GetCandidateById(candidateId); c != nil {
+		d.ValidatorAddress = c.OwnerAddress
+		d.PubKey = c.PubKey
+	}
+	b, _ := json.Marshal(d)
+	return b
+}
+
+// touchCandidate marks the candidate as changed before its row is updated, along
+// with its delegations when the address or the public key they carry changes
+func touchCandidate(tx *sql.Tx, c *Candidate) {
+	candidateMirror.Touch(CandidateKey(common.HexToAddress(c.OwnerAddress)))
+
+	var address, pubKey string
+	err := tx.QueryRow("select address, pub_key from candidates where id = ?", c.Id).Scan(&address, &pubKey)
+	if err == sql.ErrNoRows {
+		return
+	} else if err != nil {
+		panic(err)
+	}
+	if address == c.OwnerAddress && pubKey == types.PubKeyString(c.PubKey) {
+		return
+	}
+
+	candidateMirror.Touch(CandidateKey(common.HexToAddress(address)))
+	rows, err := tx.Query("select delegator_address from delegations where candidate_id = ?", c.Id)
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var delegatorAddress string
+		if err := rows.Scan(&delegatorAddress); err != nil {
+			panic(err)
+		}
+		delegationMirror.Touch(DelegationKey(common.HexToAddress(delegatorAddress), c.Id))
+	}
+}
+
+// touchDelegation marks the delegation with the id as changed before its row is updated
+func touchDelegation(tx *sql.Tx, id int64) {
+	var delegatorAddress string
+	var candidateId int64
+	err := tx.QueryRow("select delegator_address, candidate_id from delegations where id = ?", id).Scan(&delegatorAddress, &candidateId)
+	if err == sql.ErrNoRows {
+		return
+	} else if err != nil {
+		panic(err)
+	}
+	delegationMirror.Touch(DelegationKey(common.HexToAddress(delegatorAddress), candidateId))
+}
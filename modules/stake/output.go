@@ -0,0 +1,125 @@
+package stake
+
+import (
+	"github.com/CyberMiles/travis/sdk"
+	"github.com/CyberMiles/travis/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CandidateOutput is the candidate of the queries, the amounts of wei are
+// given in cmt too
+type CandidateOutput struct {
+	*Candidate
+	SharesCmt    sdk.Coin `json:"shares_cmt"`
+	MaxSharesCmt sdk.Coin `json:"max_shares_cmt"`
+}
+
+func NewCandidateOutput(c *Candidate) *CandidateOutput {
+	return &CandidateOutput{
+		Candidate:    c,
+		SharesCmt:    sdk.NewCoin(c.ParseShares()),
+		MaxSharesCmt: sdk.NewCoin(c.ParseMaxShares()),
+	}
+}
+
+func NewCandidatesOutput(candidates Candidates) []*CandidateOutput {
+	outputs := make([]*CandidateOutput, len(candidates))
+	for i, c := range candidates {
+		outputs[i] = NewCandidateOutput(c)
+	}
+	return outputs
+}
+
+// DelegationOutput is the delegation of the queries, the amounts of wei are
+// given in cmt too
+type DelegationOutput struct {
+	*Delegation
+	SharesCmt                sdk.Coin `json:"shares_cmt"`
+	DelegateAmountCmt        sdk.Coin `json:"delegate_amount_cmt"`
+	AwardAmountCmt           sdk.Coin `json:"award_amount_cmt"`
+	WithdrawAmountCmt        sdk.Coin `json:"withdraw_amount_cmt"`
+	PendingWithdrawAmountCmt sdk.Coin `json:"pending_withdraw_amount_cmt"`
+	SlashAmountCmt           sdk.Coin `json:"slash_amount_cmt"`
+}
+
+func NewDelegationOutput(d *Delegation) *DelegationOutput {
+	return &DelegationOutput{
+		Delegation:               d,
+		SharesCmt:                sdk.NewCoin(d.Shares()),
+		DelegateAmountCmt:        sdk.NewCoin(d.ParseDelegateAmount()),
+		AwardAmountCmt:           sdk.NewCoin(d.ParseAwardAmount()),
+		WithdrawAmountCmt:        sdk.NewCoin(d.ParseWithdrawAmount()),
+		PendingWithdrawAmountCmt: sdk.NewCoin(d.ParsePendingWithdrawAmount()),
+		SlashAmountCmt:           sdk.NewCoin(d.ParseSlashAmount()),
+	}
+}
+
+func NewDelegationsOutput(delegations []*Delegation) []*DelegationOutput {
+	outputs := make([]*DelegationOutput, len(delegations))
+	for i, d := range delegations {
+		outputs[i] = NewDelegationOutput(d)
+	}
+	return outputs
+}
+
+// CandidatesPageOutput is the page of candidates of the queries
+type CandidatesPageOutput struct {
+	Candidates []*CandidateOutput `json:"candidates"`
+	Next       int64              `json:"next"`
+}
+
+func NewCandidatesPageOutput(page CandidatesPage) *CandidatesPageOutput {
+	return &CandidatesPageOutput{NewCandidatesOutput(page.Candidates), page.Next}
+}
+
+// DelegationsPageOutput is the page of delegations of the queries
+type DelegationsPageOutput struct {
+	Delegations []*DelegationOutput `json:"delegations"`
+	Next        int64               `json:"next"`
+}
+
+func NewDelegationsPageOutput(page DelegationsPage) *DelegationsPageOutput {
+	return &DelegationsPageOutput{NewDelegationsOutput(page.Delegations), page.Next}
+}
+
+// UnstakeRequestOutput is the unstake request of the queries, with its amount
+// in cmt too
+type UnstakeRequestOutput struct {
+	*UnstakeRequest
+	AmountCmt sdk.Coin `json:"amount_cmt"`
+}
+
+func NewUnstakeRequestOutput(r *UnstakeRequest) *UnstakeRequestOutput {
+	return &UnstakeRequestOutput{r, sdk.NewCoin(utils.ParseInt(r.Amount))}
+}
+
+// ValidatorDelegationOutput is a delegation to a validator, with the pending
+// unstake requests of the delegator from the validator
+type ValidatorDelegationOutput struct {
+	*DelegationOutput
+	PendingUnstakeRequests []*UnstakeRequestOutput `json:"pending_unstake_requests"`
+}
+
+// ValidatorDelegationsPageOutput is the page of delegations of a validator
+type ValidatorDelegationsPageOutput struct {
+	Validator   *CandidateOutput             `json:"validator"`
+	Delegations []*ValidatorDelegationOutput `json:"delegations"`
+	Next        int64                        `json:"next"`
+}
+
+func NewValidatorDelegationsPageOutput(page *ValidatorDelegationsPage) *ValidatorDelegationsPageOutput {
+	reqs := make(map[common.Address][]*UnstakeRequestOutput)
+	for _, r := range page.UnstakeRequests {
+		reqs[r.DelegatorAddress] = append(reqs[r.DelegatorAddress], NewUnstakeRequestOutput(r))
+	}
+
+	delegations := make([]*ValidatorDelegationOutput, len(page.Delegations))
+	for i, d := range page.Delegations {
+		pending := reqs[d.DelegatorAddress]
+		if pending == nil {
+			pending = []*UnstakeRequestOutput{}
+		}
+		delegations[i] = &ValidatorDelegationOutput{NewDelegationOutput(d), pending}
+	}
+	return &ValidatorDelegationsPageOutput{NewCandidateOutput(page.Validator), delegations, page.Next}
+}
@@ -2,6 +2,10 @@ package stake
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/CyberMiles/travis/sdk"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -68,3 +72,245 @@ func queryDelegations(db *sql.DB, cond map[string]interface{}) (delegations []*D
 	}
 	return
 }
+
+// QueryValidatorSetChanges returns the validator set changes of the given block,
+// or the latest 100 changes if the height is 0
+func QueryValidatorSetChanges(height int64) (changes ValidatorSetChanges) {
+	db := getImmuDb()
+	var rows *sql.Rows
+	var err error
+	if height > 0 {
+		rows, err = db.Query("select id, candidate_id, address, old_state, new_state, old_rank, new_rank, old_tendermint_voting_power, new_tendermint_voting_power, reason, block_height from validator_set_changes where block_height = ? order by id", height)
+	} else {
+		rows, err = db.Query("select id, candidate_id, address, old_state, new_state, old_rank, new_rank, old_tendermint_voting_power, new_tendermint_voting_power, reason, block_height from validator_set_changes order by id desc limit 100")
+	}
+	if err != nil {
+		fmt.Printf("Error occurred while querying the validator set changes: %s\n", err)
+		return ValidatorSetChanges{}
+	}
+	defer rows.Close()
+	return composeValidatorSetChangeResults(rows)
+}
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// Page selects the rows in the order of their ids, from the one after the
+// cursor, which is the Next of the previous page, or after skipping Offset rows
+type Page struct {
+	Cursor int64 `json:"cursor"`
+	Offset int64 `json:"offset"`
+	Limit  int64 `json:"limit"`
+}
+
+func (p Page) limit() int64 {
+	if p.Limit <= 0 {
+		return defaultPageLimit
+	}
+	if p.Limit > maxPageLimit {
+		return maxPageLimit
+	}
+	return p.Limit
+}
+
+// CandidateFilter filters the candidates of a page, the blank fields don't filter
+type CandidateFilter struct {
+	State     string   `json:"state"`
+	Verified  string   `json:"verified"`
+	Active    string   `json:"active"`
+	MinRank   int64    `json:"minRank"`
+	MaxRank   int64    `json:"maxRank"`
+	MinShares sdk.Coin `json:"minShares"`
+	Page
+}
+
+// DelegationFilter filters the delegations of a page, the blank fields don't filter
+type DelegationFilter struct {
+	DelegatorAddress string `json:"delegatorAddress"`
+	CandidateId      int64  `json:"candidateId"`
+	State            string `json:"state"`
+	Page
+}
+
+// CandidatesPage is a page of candidates, Next is the cursor of the next page, 0 for the last one
+type CandidatesPage struct {
+	Candidates Candidates `json:"candidates"`
+	Next       int64      `json:"next"`
+}
+
+// DelegationsPage is a page of delegations, Next is the cursor of the next page, 0 for the last one
+type DelegationsPage struct {
+	Delegations []*Delegation `json:"delegations"`
+	Next        int64         `json:"next"`
+}
+
+func (f CandidateFilter) clause() (string, []interface{}) {
+	var conds []string
+	var params []interface{}
+	add := func(cond string, values ...interface{}) {
+		conds = append(conds, cond)
+		params = append(params, values...)
+	}
+
+	if f.State != "" {
+		add("state = ?", f.State)
+	}
+	if f.Verified != "" {
+		add("verified = ?", f.Verified)
+	}
+	if f.Active != "" {
+		add("active = ?", f.Active)
+	}
+	if f.MinRank > 0 {
+		add("rank >= ?", f.MinRank)
+	}
+	if f.MaxRank > 0 {
+		add("rank <= ?", f.MaxRank)
+	}
+	if f.MinShares.IsPositive() {
+		// the shares are decimal strings without leading zeros
+		min := f.MinShares.Wei()
+		add("(length(shares) > ? or (length(shares) = ? and shares >= ?))", len(min), len(min), min)
+	}
+	return pageClause(conds, params, f.Page)
+}
+
+func (f DelegationFilter) clause() (string, []interface{}) {
+	var conds []string
+	var params []interface{}
+	if f.DelegatorAddress != "" {
+		conds = append(conds, "delegator_address = ?")
+		params = append(params, common.HexToAddress(f.DelegatorAddress).String())
+	}
+	if f.CandidateId > 0 {
+		conds = append(conds, "candidate_id = ?")
+		params = append(params, f.CandidateId)
+	}
+	if f.State != "" {
+		conds = append(conds, "state = ?")
+		params = append(params, f.State)
+	}
+	return pageClause(conds, params, f.Page)
+}
+
+func pageClause(conds []string, params []interface{}, p Page) (string, []interface{}) {
+	if p.Cursor > 0 {
+		conds = append(conds, "id > ?")
+		params = append(params, p.Cursor)
+	}
+	clause := ""
+	if len(conds) > 0 {
+		clause = " where " + strings.Join(conds, " and ")
+	}
+	clause += fmt.Sprintf(" order by id limit %d", p.limit())
+	if p.Cursor <= 0 && p.Offset > 0 {
+		clause += fmt.Sprintf(" offset %d", p.Offset)
+	}
+	return clause, params
+}
+
+// QueryCandidatesPage returns a page of the candidates selected by the filter
+func QueryCandidatesPage(filter CandidateFilter) (page CandidatesPage) {
+	db := getImmuDb()
+	clause, params := filter.clause()
+	rows, err := db.Query("select id, pub_key, address, shares, voting_power, pending_voting_power,  max_shares, comp_rate, name, website, location, profile, email, verified, active, block_height, rank, state, num_of_delegators, created_at from candidates"+clause, params...)
+	if err != nil {
+		// panic(err)
+	}
+	if rows != nil {
+		defer rows.Close()
+		page.Candidates = composeCandidateResults(rows)
+	}
+	if n := len(page.Candidates); int64(n) == filter.limit() {
+		page.Next = page.Candidates[n-1].Id
+	}
+	return
+}
+
+// QueryDelegationsPage returns a page of the delegations selected by the filter
+func QueryDelegationsPage(filter DelegationFilter) (page DelegationsPage) {
+	db := getImmuDb()
+	clause, params := filter.clause()
+	rows, err := db.Query("select id, delegator_address, candidate_id, delegate_amount, award_amount, withdraw_amount, pending_withdraw_amount, slash_amount, comp_rate, voting_power, state, block_height, average_staking_date, created_at, source, completely_withdraw from delegations"+clause, params...)
+	if err != nil {
+		// panic(err)
+	}
+	if rows != nil {
+		defer rows.Close()
+		page.Delegations = composeDelegationResults(rows)
+	}
+	if n := len(page.Delegations); int64(n) == filter.limit() {
+		page.Next = page.Delegations[n-1].Id
+	}
+	return
+}
+
+// ValidatorDelegationsFilter selects a page of the delegations of a validator,
+// in the state "Y" unless another one is given
+type ValidatorDelegationsFilter struct {
+	ValidatorAddress string `json:"validatorAddress"`
+	State            string `json:"state"`
+	Page
+}
+
+// ValidatorDelegationsPage is a page of the delegations of a validator, with
+// the pending unstake requests of its delegators from the validator
+type ValidatorDelegationsPage struct {
+	Validator       *Candidate        `json:"validator"`
+	Delegations     []*Delegation     `json:"delegations"`
+	UnstakeRequests []*UnstakeRequest `json:"unstake_requests"`
+	Next            int64             `json:"next"`
+}
+
+// QueryValidatorDelegationsPage returns a page of the delegations of the
+// validator, nil if there is no such validator
+func QueryValidatorDelegationsPage(filter ValidatorDelegationsFilter) *ValidatorDelegationsPage {
+	validator := QueryCandidateByAddress(common.HexToAddress(filter.ValidatorAddress))
+	if validator == nil {
+		return nil
+	}
+
+	state := filter.State
+	if state == "" {
+		state = "Y"
+	}
+	page := QueryDelegationsPage(DelegationFilter{CandidateId: validator.Id, State: state, Page: filter.Page})
+	res := &ValidatorDelegationsPage{Validator: validator, Delegations: page.Delegations, Next: page.Next}
+	if len(page.Delegations) > 0 {
+		res.UnstakeRequests = unstakeRequestsOf(page.Delegations, queryPendingUnstakeRequests(validator.Id))
+	}
+	return res
+}
+
+// unstakeRequestsOf returns the requests of the delegators of the delegations
+func unstakeRequestsOf(delegations []*Delegation, reqs []*UnstakeRequest) (res []*UnstakeRequest) {
+	delegators := make(map[common.Address]bool)
+	for _, d := range delegations {
+		delegators[d.DelegatorAddress] = true
+	}
+	for _, req := range reqs {
+		if delegators[req.DelegatorAddress] {
+			res = append(res, req)
+		}
+	}
+	return
+}
+
+func queryPendingUnstakeRequests(candidateId int64) (reqs []*UnstakeRequest) {
+	db := getImmuDb()
+	cond := make(map[string]interface{})
+	cond["candidate_id"] = candidateId
+	cond["state"] = "PENDING"
+	clause, params := buildQueryClause(cond)
+	rows, err := db.Query("select id, delegator_address, candidate_id, initiated_block_height, performed_block_height, amount, state, actual_amount from unstake_requests"+clause, params...)
+	if err != nil {
+		// panic(err)
+	}
+	if rows != nil {
+		defer rows.Close()
+		reqs = composeUnstakeRequestResults(rows)
+	}
+	return
+}
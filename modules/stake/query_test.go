@@ -0,0 +1,70 @@
+package stake
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CyberMiles/travis/sdk"
+)
+
+func TestCandidateFilterClause(t *testing.T) {
+	clause, params := CandidateFilter{}.clause()
+	assert.Equal(t, " order by id limit 100", clause)
+	assert.Empty(t, params)
+
+	minShares, err := sdk.ParseCoin("1000cmt")
+	assert.Nil(t, err)
+	filter := CandidateFilter{
+		State:     "Validator",
+		MaxRank:   10,
+		MinShares: minShares,
+		Page:      Page{Cursor: 7, Offset: 20, Limit: 5000},
+	}
+	clause, params = filter.clause()
+	assert.Equal(t, " where state = ? and rank <= ? and (length(shares) > ? or (length(shares) = ? and shares >= ?)) and id > ? order by id limit 1000", clause)
+	assert.Equal(t, []interface{}{"Validator", int64(10), 22, 22, "1000000000000000000000", int64(7)}, params)
+}
+
+func TestDelegationFilterClause(t *testing.T) {
+	delegator := "0x38d7b32e7b5056b297baf1a1e950abbaa19ce949"
+	filter := DelegationFilter{
+		DelegatorAddress: delegator,
+		State:            "Y",
+		Page:             Page{Offset: 20, Limit: 10},
+	}
+	clause, params := filter.clause()
+	assert.Equal(t, " where delegator_address = ? and state = ? order by id limit 10 offset 20", clause)
+	assert.Equal(t, []interface{}{common.HexToAddress(delegator).String(), "Y"}, params)
+}
+
+func TestValidatorDelegationsPageUnstakeRequests(t *testing.T) {
+	assert := assert.New(t)
+
+	alice, bob, carol := common.HexToAddress("0xa1"), common.HexToAddress("0xb2"), common.HexToAddress("0xc3")
+	delegations := []*Delegation{
+		{Id: 1, DelegatorAddress: alice, DelegateAmount: "1000"},
+		{Id: 2, DelegatorAddress: bob, DelegateAmount: "2000"},
+	}
+	reqs := []*UnstakeRequest{
+		{Id: 10, DelegatorAddress: alice, Amount: "100"},
+		{Id: 11, DelegatorAddress: carol, Amount: "300"},
+		{Id: 12, DelegatorAddress: alice, Amount: "200"},
+	}
+
+	// the requests of the delegators not on the page are left out
+	matched := unstakeRequestsOf(delegations, reqs)
+	assert.Equal([]*UnstakeRequest{reqs[0], reqs[2]}, matched)
+	assert.Empty(unstakeRequestsOf(nil, reqs))
+
+	page := &ValidatorDelegationsPage{Validator: &Candidate{Id: 1}, Delegations: delegations, UnstakeRequests: matched, Next: 2}
+	out := NewValidatorDelegationsPageOutput(page)
+	assert.Equal(int64(2), out.Next)
+	assert.Len(out.Delegations, 2)
+	assert.Len(out.Delegations[0].PendingUnstakeRequests, 2)
+	assert.Equal(int64(10), out.Delegations[0].PendingUnstakeRequests[0].Id)
+	assert.Equal(int64(12), out.Delegations[0].PendingUnstakeRequests[1].Id)
+	assert.NotNil(out.Delegations[1].PendingUnstakeRequests, "the delegators without a request have an empty list")
+	assert.Empty(out.Delegations[1].PendingUnstakeRequests)
+}
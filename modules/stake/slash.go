@@ -9,6 +9,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 
+	"github.com/CyberMiles/travis/metrics"
 	"github.com/CyberMiles/travis/sdk"
 	"github.com/CyberMiles/travis/types"
 	"github.com/CyberMiles/travis/utils"
@@ -16,6 +17,9 @@ import (
 
 var cdc = amino.NewCodec()
 
+// the bad proposer is slashed on commit, after the validator set of its block is updated
+const badProposerReason = "Bad block proposer"
+
 type Absence struct {
 	Count           int16
 	LastBlockHeight int64
@@ -72,7 +76,7 @@ func (av AbsentValidators) Contains(pk types.PubKey) bool {
 
 func SlashByzantineValidator(pubKey types.PubKey, blockTime, blockHeight int64) (err error) {
 	slashRatio := utils.GetParams().SlashRatio
-	err = slash(pubKey, "Byzantine validator", slashRatio, blockTime, blockHeight, true)
+	err = slash(pubKey, "byzantine", "Byzantine validator", slashRatio, blockTime, blockHeight, true)
 	if err != nil {
 		return err
 	}
@@ -84,21 +88,21 @@ func SlashAbsentValidator(pubKey types.PubKey, absence *Absence, blockTime, bloc
 	maxSlashBlocks := utils.GetParams().MaxSlashBlocks
 	if absence.GetCount() == maxSlashBlocks {
 		reason := fmt.Sprintf("Absent for up to %d consecutive blocks", maxSlashBlocks)
-		err = slash(pubKey, reason, slashRatio, blockTime, blockHeight, utils.GetParams().SlashEnabled)
+		err = slash(pubKey, "absent", reason, slashRatio, blockTime, blockHeight, utils.GetParams().SlashEnabled)
 	}
 	return
 }
 
 func SlashBadProposer(pubKey types.PubKey, blockTime, blockHeight int64) (err error) {
 	slashRatio := utils.GetParams().SlashRatio
-	err = slash(pubKey, "Bad block proposer", slashRatio, blockTime, blockHeight, true)
+	err = slash(pubKey, "bad_proposer", badProposerReason, slashRatio, blockTime, blockHeight, true)
 	if err != nil {
 		return err
 	}
 	return
 }
 
-func slash(pubKey types.PubKey, reason string, slashRatio sdk.Rat, blockTime, blockHeight int64, slashEnabled bool) (err error) {
+func slash(pubKey types.PubKey, kind, reason string, slashRatio sdk.Rat, blockTime, blockHeight int64, slashEnabled bool) (err error) {
 	totalDeduction := sdk.NewInt(0)
 	v := GetCandidateByPubKey(pubKey)
 	if v == nil {
@@ -125,6 +129,16 @@ func slash(pubKey types.PubKey, reason string, slashRatio sdk.Rat, blockTime, bl
 	// Save slash history
 	slash := &Slash{CandidateId: v.Id, SlashRatio: slashRatio, SlashAmount: totalDeduction, Reason: reason, CreatedAt: blockTime, BlockHeight: blockHeight}
 	saveSlash(slash)
+	if metrics.Enabled() {
+		metrics.Slashes.WithLabelValues(kind).Inc()
+	}
+
+	types.EventBus.Publish(types.EventSlash, blockHeight, []common.Address{common.HexToAddress(v.OwnerAddress)}, map[string]interface{}{
+		"candidateId": v.Id,
+		"reason":      reason,
+		"slashRatio":  slashRatio,
+		"slashAmount": totalDeduction.String(),
+	})
 
 	return
 }
@@ -186,7 +186,10 @@ func (cs Candidates) updateVotingPower(blockHeight int64, updates PubKeyUpdates)
 	}
 
 	cs.Sort()
+	// the set was last updated CalVPInterval blocks ago
+	slashed := getSlashedCandidates(blockHeight-int64(utils.GetParams().CalVPInterval), blockHeight)
 	for i, c := range cs {
+		oldState, oldRank, oldTvp := c.State, c.Rank, c.TendermintVotingPower
 		// truncate the power
 		if i >= int(utils.GetParams().MaxVals) {
 			if i >= (int(utils.GetParams().MaxVals + utils.GetParams().BackupVals)) {
@@ -212,10 +215,63 @@ func (cs Candidates) updateVotingPower(blockHeight int64, updates PubKeyUpdates)
 
 		c.Rank = int64(i)
 		updateCandidate(c)
+
+		if oldState != c.State || oldTvp != c.TendermintVotingPower {
+			saveValidatorSetChange(&ValidatorSetChange{
+				CandidateId:              c.Id,
+				Address:                  common.HexToAddress(c.OwnerAddress),
+				OldState:                 oldState,
+				NewState:                 c.State,
+				OldRank:                  oldRank,
+				NewRank:                  c.Rank,
+				OldTendermintVotingPower: oldTvp,
+				NewTendermintVotingPower: c.TendermintVotingPower,
+				Reason:                   valChangeReason(c, oldRank, slashed),
+				BlockHeight:              blockHeight,
+			})
+		}
+
+		if oldState != c.State {
+			publishCandidateStateChanged(blockHeight, c, oldState, "")
+		}
 	}
 	return cs
 }
 
+// publishCandidateStateChanged emits the candidate state change event,
+// action is the candidacy tx that caused it, empty when the ranking did.
+func publishCandidateStateChanged(blockHeight int64, c *Candidate, oldState, action string) {
+	data := map[string]interface{}{
+		"candidateId": c.Id,
+		"oldState":    oldState,
+		"newState":    c.State,
+		"rank":        c.Rank,
+		"active":      c.Active,
+	}
+	if action != "" {
+		data["action"] = action
+	}
+	types.EventBus.Publish(types.EventCandidateStateChanged, blockHeight, []common.Address{common.HexToAddress(c.OwnerAddress)}, data)
+}
+
+// valChangeReason tells why the candidate entered or left the validator set,
+// slashed holds the candidates slashed since the last update of the set
+func valChangeReason(c *Candidate, oldRank int64, slashed map[int64]struct{}) string {
+	if _, ok := slashed[c.Id]; ok {
+		return ValChangeSlashed
+	}
+	if c.Active == "N" {
+		return ValChangeDeactivated
+	}
+	if c.Rank == oldRank {
+		return ValChangePowerChange
+	}
+	if c.Rank > oldRank {
+		return ValChangeRankDrop
+	}
+	return ValChangeRankRise
+}
+
 // Validators - get the most recent updated validator set from the
 // Candidates. These bonds are already sorted by VotingPower from
 // the UpdateVotingPower function which is the only function which
@@ -352,10 +408,23 @@ func UpdateValidatorSet(store state.SimpleDB, blockHeight int64) (change []abci.
 	return
 }
 
-// Deactivate the validators
-func (vs Validators) Deactivate() {
+// Deactivate the validators retired by the governance
+func (vs Validators) Deactivate(blockHeight int64) {
 	// update voting power
 	for _, v := range vs {
+		saveValidatorSetChange(&ValidatorSetChange{
+			CandidateId:              v.Id,
+			Address:                  common.HexToAddress(v.OwnerAddress),
+			OldState:                 v.State,
+			NewState:                 v.State,
+			OldRank:                  v.Rank,
+			NewRank:                  v.Rank,
+			OldTendermintVotingPower: v.TendermintVotingPower,
+			NewTendermintVotingPower: 0,
+			Reason:                   ValChangeRetired,
+			BlockHeight:              blockHeight,
+		})
+
 		v.Active = "N"
 		v.VotingPower = 0
 		c := Candidate(v)
@@ -533,6 +602,32 @@ func (s *Slash) Hash() []byte {
 	return hasher.Sum(nil)
 }
 
+// Reason codes of the validator set changes
+const (
+	ValChangeRankRise    = "rank_rise"
+	ValChangeRankDrop    = "rank_drop"
+	ValChangeDeactivated = "deactivated"
+	ValChangeSlashed     = "slashed"
+	ValChangeRetired     = "retired"
+	ValChangePowerChange = "power_change"
+)
+
+type ValidatorSetChange struct {
+	Id                       int64          `json:"id"`
+	CandidateId              int64          `json:"candidate_id"`
+	Address                  common.Address `json:"address"`
+	OldState                 string         `json:"old_state"`
+	NewState                 string         `json:"new_state"`
+	OldRank                  int64          `json:"old_rank"`
+	NewRank                  int64          `json:"new_rank"`
+	OldTendermintVotingPower int64          `json:"old_tendermint_voting_power"`
+	NewTendermintVotingPower int64          `json:"new_tendermint_voting_power"`
+	Reason                   string         `json:"reason"`
+	BlockHeight              int64          `json:"block_height"`
+}
+
+type ValidatorSetChanges []*ValidatorSetChange
+
 type UnstakeRequest struct {
 	Id                   int64          `json:"id"`
 	DelegatorAddress     common.Address `json:"delegator_address"`
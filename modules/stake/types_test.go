@@ -0,0 +1,58 @@
+package stake
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CyberMiles/travis/sdk"
+)
+
+func TestValChangeReason(t *testing.T) {
+	assert := assert.New(t)
+
+	c := &Candidate{Id: 1, Active: "Y", Rank: 3}
+	none := map[int64]struct{}{}
+	assert.Equal(ValChangePowerChange, valChangeReason(c, 3, none))
+	assert.Equal(ValChangeRankDrop, valChangeReason(c, 2, none))
+	assert.Equal(ValChangeRankRise, valChangeReason(c, 4, none))
+
+	c.Active = "N"
+	assert.Equal(ValChangeDeactivated, valChangeReason(c, 3, none))
+	assert.Equal(ValChangeSlashed, valChangeReason(c, 3, map[int64]struct{}{1: {}}))
+}
+
+func TestGetSlashedCandidates(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.Nil(err)
+	defer db.Close()
+	_, err = db.Exec("create table slashes(id integer not null primary key autoincrement, candidate_id integer not null, slash_ratio integer default 0, slash_amount text not null, reason text not null default '', created_at integer not null, block_height integer not null, hash text not null default '')")
+	require.Nil(err)
+
+	tx, err := db.Begin()
+	require.Nil(err)
+	SetDeliverSqlTx(tx)
+	defer func() {
+		ResetDeliverSqlTx()
+		tx.Rollback()
+	}()
+
+	save := func(candidateId, height int64, reason string) {
+		saveSlash(&Slash{CandidateId: candidateId, SlashRatio: sdk.NewRat(1, 10), SlashAmount: sdk.ZeroInt, Reason: reason, BlockHeight: height})
+	}
+	save(1, 10, "Byzantine validator")
+	save(2, 10, badProposerReason)
+	save(3, 11, "Byzantine validator")
+	save(4, 12, badProposerReason)
+	save(5, 13, "Byzantine validator")
+
+	// the set was updated at 10, the slashes of its begin block were reported then
+	assert.Equal(map[int64]struct{}{2: {}, 3: {}, 4: {}}, getSlashedCandidates(10, 12))
+	assert.Equal(map[int64]struct{}{4: {}}, getSlashedCandidates(12, 12))
+	assert.Empty(getSlashedCandidates(13, 14))
+}
@@ -66,3 +66,9 @@ func GetTrustedProvider() lite.Provider {
 func GetProviders() (trusted lite.Provider, source lite.Provider) {
 	return GetTrustedProvider(), GetSourceProvider()
 }
+
+// GetCertifier creates a certifier from the trusted commits of the chain
+func GetCertifier() (*lite.Inquiring, error) {
+	trust, source := GetProviders()
+	return client.GetCertifier(GetChainID(), trust, source)
+}
@@ -0,0 +1,83 @@
+package query
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/CyberMiles/travis/sdk/client"
+	"github.com/CyberMiles/travis/sdk/client/commands"
+	"github.com/CyberMiles/travis/sdk/state"
+)
+
+// Verifier checks the value the node returned is the one proven
+type Verifier func(proof *state.StateProof, value []byte) error
+
+// Get queries the node at the height given by --height, the value is checked
+// against a certified commit unless --trust-node is set
+func Get(path string, data []byte, verify Verifier) ([]byte, error) {
+	height := viper.GetInt64(FlagHeight)
+	if viper.GetBool(commands.FlagTrustNode) {
+		return GetTrusted(path, data, height)
+	}
+
+	cert, err := commands.GetCertifier()
+	if err != nil {
+		return nil, err
+	}
+	value, proof, err := client.GetWithProof(path, data, height, commands.GetNode(), cert)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(proof, value); err != nil {
+		return nil, errors.Wrap(err, "The value returned by the node is not the proven one")
+	}
+	return value, nil
+}
+
+// GetTrusted queries the node without any proof
+func GetTrusted(path string, data []byte, height int64) ([]byte, error) {
+	resp, err := commands.GetNode().ABCIQueryWithOptions(path, data, rpcclient.ABCIQueryOptions{Trusted: true, Height: height})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response.IsErr() {
+		return nil, errors.Errorf("Query failed: (%d) %s", resp.Response.Code, resp.Response.Log)
+	}
+	return resp.Response.Value, nil
+}
+
+// GetUnprovable queries data the node can't prove, which needs --trust-node
+func GetUnprovable(path string, data []byte) ([]byte, error) {
+	if !viper.GetBool(commands.FlagTrustNode) {
+		return nil, errors.Errorf("The response of %s can't be proven, use --%s to trust the node", path, commands.FlagTrustNode)
+	}
+	return GetTrusted(path, data, viper.GetInt64(FlagHeight))
+}
+
+// VerifyKey checks the value is the one of the key, or the key has no value if it's empty
+func VerifyKey(key []byte) Verifier {
+	return func(proof *state.StateProof, value []byte) error {
+		if len(value) == 0 {
+			return proof.VerifyAbsence(key)
+		}
+		return proof.VerifyItem(key, value)
+	}
+}
+
+// VerifyRange checks the value is the json array of the values of all the keys with the prefix
+func VerifyRange(prefix []byte) Verifier {
+	return func(proof *state.StateProof, value []byte) error {
+		var raws []json.RawMessage
+		if err := json.Unmarshal(value, &raws); err != nil {
+			return err
+		}
+		values := make([][]byte, len(raws))
+		for i, raw := range raws {
+			values[i] = raw
+		}
+		return proof.VerifyRange(prefix, values)
+	}
+}
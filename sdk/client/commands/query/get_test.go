@@ -0,0 +1,56 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/iavl"
+	dbm "github.com/tendermint/tendermint/libs/db"
+
+	"github.com/CyberMiles/travis/sdk/state"
+)
+
+func TestVerifiers(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+
+	prefix := []byte{0x07}
+	store := state.NewBonsai(iavl.NewVersionedTree(dbm.NewMemDB(), 0))
+	store.Set([]byte{0x07, 0x01}, []byte(`{"id":1}`))
+	store.Set([]byte{0x07, 0x02}, []byte(`{"id":2}`))
+	store.Set([]byte{0x08, 0x01}, []byte(`{"id":3}`))
+	root, version, err := store.Tree.SaveVersion()
+	require.Nil(err)
+
+	appHash := state.AppHash(nil, root, nil)
+	proven := func(proof *iavl.RangeProof) *state.StateProof {
+		p := proven(proof)
+		require.Nil(p.Verify(appHash))
+		return p
+	}
+
+	// the key
+	_, proof, err := store.Tree.GetVersionedWithProof([]byte{0x07, 0x01}, version)
+	require.Nil(err)
+	p := proven(proof)
+	assert.Nil(VerifyKey([]byte{0x07, 0x01})(p, []byte(`{"id":1}`)))
+	assert.NotNil(VerifyKey([]byte{0x07, 0x01})(p, []byte(`{"id":9}`)))
+	assert.NotNil(VerifyKey([]byte{0x07, 0x01})(p, nil))
+
+	// a missing key
+	_, proof, err = store.Tree.GetVersionedWithProof([]byte{0x07, 0x03}, version)
+	require.Nil(err)
+	p = proven(proof)
+	assert.Nil(VerifyKey([]byte{0x07, 0x03})(p, nil))
+	assert.NotNil(VerifyKey([]byte{0x07, 0x03})(p, []byte(`{"id":3}`)))
+
+	// the range
+	_, _, proof, err = store.Tree.GetVersionedRangeWithProof(prefix, state.PrefixEnd(prefix), 0, version)
+	require.Nil(err)
+	p = proven(proof)
+	assert.Nil(VerifyRange(prefix)(p, []byte(`[{"id":1},{"id":2}]`)))
+	assert.NotNil(VerifyRange(prefix)(p, []byte(`[{"id":1},{"id":9}]`)))
+	assert.NotNil(VerifyRange(prefix)(p, []byte(`[{"id":1}]`)))
+	assert.NotNil(VerifyRange(prefix)(p, []byte(`[{"id":1},{"id":2},{"id":3}]`)))
+	assert.NotNil(VerifyRange(prefix)(p, []byte(`not json`)))
+}
@@ -25,6 +25,5 @@ data to other peers as needed.
 func init() {
 	RootCmd.PersistentFlags().Int(FlagHeight, 0, "Height to query (skip to use latest block)")
 	RootCmd.PersistentFlags().Bool(commands.FlagTrustNode, false,
-		"DANGEROUS: blindly trust all results from the server")
-	RootCmd.PersistentFlags().MarkHidden(commands.FlagTrustNode)
+		"DANGEROUS: blindly trust all results from the server, skip the proof verification")
 }
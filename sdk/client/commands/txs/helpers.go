@@ -23,7 +23,6 @@ import (
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rlp"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
@@ -61,16 +60,29 @@ func DoTx(tx sdk.Tx) (err error) {
 	address := viper.GetString(FlagAddress)
 	from := common.HexToAddress(address)
 
-	prompt := fmt.Sprintf("Please enter passphrase for %s: ", address)
-	passphrase, err := getPassword(prompt)
-	if err != nil {
-		return err
+	if viper.GetBool(FlagGenerateOnly) {
+		return generateTx(tx, from)
 	}
 
-	txBytes, err := wrapAndSign(tx, from, passphrase)
+	var txBytes []byte
+	if endpoint := viper.GetString(FlagSigner); endpoint != "" {
+		txBytes, err = wrapAndSignExternal(tx, from, endpoint)
+	} else {
+		prompt := fmt.Sprintf("Please enter passphrase for %s: ", address)
+		var passphrase string
+		if passphrase, err = getPassword(prompt); err != nil {
+			return err
+		}
+		txBytes, err = wrapAndSign(tx, from, passphrase)
+	}
 	if err != nil {
 		return err
 	}
+	return broadcast(txBytes)
+}
+
+// broadcast posts the signed tx and displays the result
+func broadcast(txBytes []byte) error {
 	commit := viper.GetString(FlagType)
 	if commit == "commit" {
 		bres, err := broadcastTxCommit(txBytes)
@@ -95,19 +107,11 @@ func DoTx(tx sdk.Tx) (err error) {
 }
 
 func wrapAndSign(tx sdk.Tx, from common.Address, passphrase string) (hexutil.Bytes, error) {
-	data, err := json.Marshal(tx)
+	utx, err := newUnsignedTx(tx, from, getNonce(from))
 	if err != nil {
 		return nil, err
 	}
 
-	ethTx := types.NewContractCreation(
-		getNonce(from),
-		big.NewInt(0),
-		0,
-		big.NewInt(0),
-		data,
-	)
-
 	am, _, _ := commons.MakeAccountManager()
 	_, err = commons.UnlockAccount(am, from, passphrase, nil)
 	if err != nil {
@@ -116,7 +120,10 @@ func wrapAndSign(tx sdk.Tx, from common.Address, passphrase string) (hexutil.Byt
 
 	account := accounts.Account{Address: from}
 	wallet, err := am.Find(account)
-	signed, err := wallet.SignTx(account, ethTx, big.NewInt(viper.GetInt64(FlagVMChainId)))
+	if err != nil {
+		return nil, err
+	}
+	signed, err := wallet.SignTx(account, utx.EthTx(), big.NewInt(utx.ChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -129,6 +136,19 @@ func wrapAndSign(tx sdk.Tx, from common.Address, passphrase string) (hexutil.Byt
 	return encodedTx, nil
 }
 
+// wrapAndSignExternal has the tx signed by the external signer, which holds the key of the sender
+func wrapAndSignExternal(tx sdk.Tx, from common.Address, endpoint string) (hexutil.Bytes, error) {
+	utx, err := newUnsignedTx(tx, from, getNonce(from))
+	if err != nil {
+		return nil, err
+	}
+	signed, err := signWithExternal(utx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(signed)
+}
+
 func getNonce(addr common.Address) uint64 {
 	//add the nonce tx layer to the tx
 	input := viper.GetInt(FlagNonce)
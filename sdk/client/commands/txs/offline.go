@@ -0,0 +1,245 @@
+package txs
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/CyberMiles/travis/commons"
+	"github.com/CyberMiles/travis/sdk"
+	"github.com/CyberMiles/travis/signer"
+)
+
+// nolint
+const (
+	FlagGenerateOnly = "generate-only"
+	FlagKeyFile      = "key-file"
+	FlagOut          = "out"
+	FlagSigner       = "signer"
+)
+
+// UnsignedTx is a transaction generated to be signed on another machine, all
+// that the signature covers is explicit so it can be reviewed before signing
+type UnsignedTx struct {
+	From     common.Address  `json:"from"`
+	Nonce    uint64          `json:"nonce"`
+	ChainId  int64           `json:"chain_id"`
+	Gas      uint64          `json:"gas"`
+	GasPrice string          `json:"gas_price"`
+	Tx       json.RawMessage `json:"tx"`
+}
+
+func newUnsignedTx(tx sdk.Tx, from common.Address, nonce uint64) (*UnsignedTx, error) {
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasPrice, ok := new(big.Int).SetString(viper.GetString(FlagGasPrice), 10)
+	if !ok || gasPrice.Sign() < 0 {
+		return nil, errors.Errorf("invalid gas price: %s", viper.GetString(FlagGasPrice))
+	}
+
+	return &UnsignedTx{
+		From:     from,
+		Nonce:    nonce,
+		ChainId:  viper.GetInt64(FlagVMChainId),
+		Gas:      uint64(viper.GetInt64(FlagGas)),
+		GasPrice: gasPrice.String(),
+		Tx:       data,
+	}, nil
+}
+
+// EthTx is the vm transaction carrying the tx
+func (utx *UnsignedTx) EthTx() *types.Transaction {
+	gasPrice, _ := new(big.Int).SetString(utx.GasPrice, 10)
+	return types.NewContractCreation(utx.Nonce, big.NewInt(0), utx.Gas, gasPrice, utx.Tx)
+}
+
+// ValidateBasic checks the tx can be signed
+func (utx *UnsignedTx) ValidateBasic() error {
+	if utx.From == (common.Address{}) {
+		return errors.New("the tx has no sender")
+	}
+	if utx.ChainId <= 0 {
+		return errors.Errorf("invalid vm chain id: %d", utx.ChainId)
+	}
+	if gasPrice, ok := new(big.Int).SetString(utx.GasPrice, 10); !ok || gasPrice.Sign() < 0 {
+		return errors.Errorf("invalid gas price: %s", utx.GasPrice)
+	}
+	var tx sdk.Tx
+	if err := json.Unmarshal(utx.Tx, &tx); err != nil {
+		return errors.Wrap(err, "invalid tx")
+	}
+	return nil
+}
+
+// generateTx writes the unsigned tx, the nonce must be given as the node
+// of the signing machine may not be reachable
+func generateTx(tx sdk.Tx, from common.Address) error {
+	if from == (common.Address{}) {
+		return errors.New("--address is required to generate a tx")
+	}
+	nonce := viper.GetInt(FlagNonce)
+	if nonce < 0 {
+		return errors.New("--nonce is required to generate a tx")
+	}
+
+	utx, err := newUnsignedTx(tx, from, uint64(nonce))
+	if err != nil {
+		return err
+	}
+	js, err := json.MarshalIndent(utx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeOutput(outputFile(), append(js, '\n'))
+}
+
+// SignCmd signs a tx generated with --generate-only, it needs no node
+var SignCmd = &cobra.Command{
+	Use:   "sign",
+	Short: "Sign a tx generated with --generate-only, with the keystore or a raw key file",
+	RunE:  doSign,
+}
+
+// BroadcastCmd posts a tx signed with the sign command
+var BroadcastCmd = &cobra.Command{
+	Use:   "broadcast",
+	Short: "Broadcast a tx signed with the sign command",
+	RunE:  doBroadcast,
+}
+
+func init() {
+	RootCmd.PersistentFlags().Bool(FlagGenerateOnly, false, "write the unsigned tx to the --prepare file, or stdout, instead of signing and posting it")
+	RootCmd.PersistentFlags().String(FlagSigner, "", "url or local socket of an external signer to sign with, instead of the keystore")
+
+	SignCmd.Flags().String(FlagIn, "", "file with the unsigned tx, stdin if not given")
+	SignCmd.Flags().String(FlagOut, "-", "file to write the signed tx to, - for stdout")
+	SignCmd.Flags().String(FlagKeyFile, "", "file with the hex private key to sign with, instead of the keystore")
+	SignCmd.Flags().String(FlagSigner, "", "url or local socket of an external signer to sign with, instead of the keystore")
+
+	BroadcastCmd.Flags().String(FlagIn, "", "file with the signed tx, stdin if not given")
+	BroadcastCmd.Flags().String(FlagType, "commit", "type(sync|commit) of broadcast tx to tendermint")
+}
+
+func doSign(cmd *cobra.Command, args []string) error {
+	raw, err := readInput(viper.GetString(FlagIn))
+	if err != nil {
+		return err
+	}
+	utx := new(UnsignedTx)
+	if err := json.Unmarshal(raw, utx); err != nil {
+		return errors.Wrap(err, "invalid unsigned tx")
+	}
+	if err := utx.ValidateBasic(); err != nil {
+		return err
+	}
+
+	var signed *types.Transaction
+	if endpoint := viper.GetString(FlagSigner); endpoint != "" {
+		signed, err = signWithExternal(utx, endpoint)
+	} else if keyFile := viper.GetString(FlagKeyFile); keyFile != "" {
+		signed, err = signWithKeyFile(utx, keyFile)
+	} else {
+		signed, err = signWithKeystore(utx)
+	}
+	if err != nil {
+		return err
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return err
+	}
+	return writeOutput(viper.GetString(FlagOut), []byte(hexutil.Encode(encodedTx)+"\n"))
+}
+
+func signWithKeyFile(utx *UnsignedTx, keyFile string) (*types.Transaction, error) {
+	key, err := crypto.LoadECDSA(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid key file")
+	}
+	if addr := crypto.PubkeyToAddress(key.PublicKey); addr != utx.From {
+		return nil, errors.Errorf("the key is the one of %s, not of the sender %s", addr.Hex(), utx.From.Hex())
+	}
+	return signWithKey(utx, key)
+}
+
+func signWithKey(utx *UnsignedTx, key *ecdsa.PrivateKey) (*types.Transaction, error) {
+	s := types.NewEIP155Signer(big.NewInt(utx.ChainId))
+	return types.SignTx(utx.EthTx(), s, key)
+}
+
+// signWithExternal has the tx signed by the external signer, which checks
+// the tx is unchanged and signed by the sender
+func signWithExternal(utx *UnsignedTx, endpoint string) (*types.Transaction, error) {
+	ext, err := signer.NewExternal(endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to the external signer")
+	}
+	defer ext.Close()
+	return ext.SignTx(utx.From, utx.EthTx(), big.NewInt(utx.ChainId))
+}
+
+func signWithKeystore(utx *UnsignedTx) (*types.Transaction, error) {
+	prompt := fmt.Sprintf("Please enter passphrase for %s: ", utx.From.Hex())
+	passphrase, err := getPassword(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	am, _, err := commons.MakeAccountManager()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = commons.UnlockAccount(am, utx.From, passphrase, nil); err != nil {
+		return nil, err
+	}
+	account := accounts.Account{Address: utx.From}
+	wallet, err := am.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	return wallet.SignTx(account, utx.EthTx(), big.NewInt(utx.ChainId))
+}
+
+func doBroadcast(cmd *cobra.Command, args []string) error {
+	raw, err := readInput(viper.GetString(FlagIn))
+	if err != nil {
+		return err
+	}
+	txBytes, err := hexutil.Decode(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return errors.Wrap(err, "invalid signed tx")
+	}
+	// make sure it's a signed tx before posting it
+	var tx types.Transaction
+	if err := rlp.DecodeBytes(txBytes, &tx); err != nil {
+		return errors.Wrap(err, "invalid signed tx")
+	}
+	if v, _, _ := tx.RawSignatureValues(); v == nil || v.Sign() == 0 {
+		return errors.New("the tx is not signed")
+	}
+	return broadcast(txBytes)
+}
+
+func outputFile() string {
+	if prep := viper.GetString(FlagPrepare); prep != "" {
+		return prep
+	}
+	return "-"
+}
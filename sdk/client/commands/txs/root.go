@@ -20,6 +20,8 @@ const (
 	FlagType      = "type"
 	FlagNonce     = "nonce"
 	FlagVMChainId = "vm-chain-id"
+	FlagGas       = "gas"
+	FlagGasPrice  = "gas-price"
 )
 
 // RootCmd represents the base command when called without any subcommands
@@ -38,6 +40,8 @@ func init() {
 	RootCmd.PersistentFlags().String(FlagType, "commit", "type(sync|commit) of broadcast tx to tendermint")
 	RootCmd.PersistentFlags().Int(FlagNonce, -1, "Sequence number for this transaction")
 	RootCmd.PersistentFlags().Int64(FlagVMChainId, 19, "20: staging, 19: testnet, 18: mainnet")
+	RootCmd.PersistentFlags().Uint64(FlagGas, 0, "Gas limit of the transaction, 0 to be charged with the default gas price")
+	RootCmd.PersistentFlags().String(FlagGasPrice, "0", "Gas price of the transaction in wei, must not be lower than the gas_price parameter")
 }
 
 func doRawTx(cmd *cobra.Command, args []string) error {
@@ -1,8 +1,11 @@
 package client
 
 import (
+	"github.com/pkg/errors"
+
 	"github.com/tendermint/tendermint/lite"
 	certclient "github.com/tendermint/tendermint/lite/client"
+	liteErr "github.com/tendermint/tendermint/lite/errors"
 	"github.com/tendermint/tendermint/lite/files"
 
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
@@ -27,3 +30,17 @@ func GetLocalProvider(dir string) lite.Provider {
 		files.NewProvider(dir),
 	)
 }
+
+// GetCertifier creates a certifier starting from the latest trusted commit, it
+// follows the changes of the validator set by bisection, storing the commits
+// it certifies along the way
+func GetCertifier(chainID string, trust lite.Provider, source lite.Provider) (*lite.Inquiring, error) {
+	fc, err := trust.LatestCommit()
+	if liteErr.IsCommitNotFoundErr(err) {
+		return nil, errors.New("Please run init first to establish a root of trust")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lite.NewInquiring(chainID, fc, trust, source), nil
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"github.com/pkg/errors"
+	"github.com/tendermint/go-amino"
+
+	"github.com/tendermint/tendermint/lite"
+	"github.com/tendermint/tendermint/lite/proxy"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/CyberMiles/travis/sdk/state"
+)
+
+var cdc = amino.NewCodec()
+
+// GetWithProof queries the node for a value along with its state proof, and
+// checks the proof leads to the app hash of a certified commit. The caller
+// checks the value is the one of the key it expects.
+func GetWithProof(path string, data []byte, height int64, node rpcclient.Client, cert lite.Certifier) ([]byte, *state.StateProof, error) {
+	resp, err := node.ABCIQueryWithOptions(path, data, rpcclient.ABCIQueryOptions{Height: height})
+	if err != nil {
+		return nil, nil, err
+	}
+	res := resp.Response
+	if res.IsErr() {
+		return nil, nil, errors.Errorf("Query failed: (%d) %s", res.Code, res.Log)
+	}
+	if len(res.Proof) == 0 {
+		return nil, nil, errors.New("The node returned no proof")
+	}
+
+	proof := new(state.StateProof)
+	if err := cdc.UnmarshalBinary(res.Proof, proof); err != nil {
+		return nil, nil, errors.Wrap(err, "Invalid proof")
+	}
+	if proof.Height != res.Height {
+		return nil, nil, errors.Errorf("The proof is for height %d, not %d", proof.Height, res.Height)
+	}
+
+	// the app hash of a block is in the header of the next one
+	commit, err := proxy.GetCertifiedCommit(res.Height+1, node, cert)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Couldn't certify the commit")
+	}
+	if err := proof.Verify(commit.Header.AppHash); err != nil {
+		return nil, nil, errors.Wrap(err, "Invalid proof")
+	}
+	return res.Value, proof, nil
+}
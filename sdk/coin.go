@@ -0,0 +1,124 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// nolint
+const (
+	CoinUnitCmt = "cmt"
+	CoinUnitWei = "wei"
+
+	cmtDecimals = 18
+)
+
+var coinRegexp = regexp.MustCompile(`^([0-9]+)(\.([0-9]+))?\s*(cmt|wei)$`)
+
+// Coin is an amount of wei, it's parsed from and printed with its unit as in
+// "1000cmt", "1.5cmt" or "500wei", so that the amounts can't be taken for
+// ones 10^18 times smaller. A number without unit is rejected, except a hex
+// one which is the amount of wei of the json-rpc, and 0.
+type Coin struct {
+	Int
+}
+
+func NewCoin(wei Int) Coin {
+	return Coin{wei}
+}
+
+// NewCoinFromWei returns the coin of a decimal amount of wei, the way the
+// amounts are stored
+func NewCoinFromWei(wei string) (Coin, bool) {
+	i, ok := new(big.Int).SetString(wei, 10)
+	if !ok {
+		return Coin{}, false
+	}
+	return Coin{NewIntFromBigInt(i)}, true
+}
+
+// ParseCoin parses "1000cmt", "1.5cmt", "500wei" or "0x1f4"
+func ParseCoin(s string) (Coin, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if strings.HasPrefix(s, "0x") {
+		i, ok := new(big.Int).SetString(s[2:], 16)
+		if !ok || i.Sign() < 0 {
+			return Coin{}, fmt.Errorf("invalid hex amount %q", s)
+		}
+		return Coin{NewIntFromBigInt(i)}, nil
+	}
+
+	// zero is the same in any unit
+	if s == "0" {
+		return Coin{ZeroInt}, nil
+	}
+
+	m := coinRegexp.FindStringSubmatch(s)
+	if m == nil {
+		if _, ok := new(big.Rat).SetString(s); ok {
+			return Coin{}, fmt.Errorf("amount %q has no unit, use %q like 1.5cmt or %q like 500wei", s, CoinUnitCmt, CoinUnitWei)
+		}
+		return Coin{}, fmt.Errorf("invalid amount %q, use 1.5cmt or 500wei", s)
+	}
+	whole, fraction, unit := m[1], m[3], m[4]
+
+	decimals := 0
+	if unit == CoinUnitCmt {
+		decimals = cmtDecimals
+	}
+	if len(fraction) > decimals {
+		return Coin{}, fmt.Errorf("amount %q is smaller than a wei", s)
+	}
+	i, _ := new(big.Int).SetString(whole+fraction+strings.Repeat("0", decimals-len(fraction)), 10)
+	return Coin{NewIntFromBigInt(i)}, nil
+}
+
+// Wei returns the decimal amount of wei, the way the amounts are stored
+func (c Coin) Wei() string {
+	if c.Int.Int == nil {
+		return "0"
+	}
+	return c.Int.String()
+}
+
+// String returns the amount in cmt, "1.5cmt"
+func (c Coin) String() string {
+	wei := c.Wei()
+	sign := ""
+	if strings.HasPrefix(wei, "-") {
+		sign, wei = "-", wei[1:]
+	}
+	if len(wei) <= cmtDecimals {
+		wei = strings.Repeat("0", cmtDecimals-len(wei)+1) + wei
+	}
+	whole, fraction := wei[:len(wei)-cmtDecimals], strings.TrimRight(wei[len(wei)-cmtDecimals:], "0")
+	if fraction != "" {
+		return sign + whole + "." + fraction + CoinUnitCmt
+	}
+	return sign + whole + CoinUnitCmt
+}
+
+func (c Coin) IsPositive() bool {
+	return c.Int.Int != nil && c.Int.Sign() > 0
+}
+
+func (c Coin) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+// UnmarshalJSON takes the strings of ParseCoin
+func (c *Coin) UnmarshalJSON(b []byte) (err error) {
+	var text string
+	if err = json.Unmarshal(b, &text); err != nil {
+		return
+	}
+	coin, err := ParseCoin(text)
+	if err != nil {
+		return
+	}
+	*c = coin
+	return
+}
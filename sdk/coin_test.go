@@ -0,0 +1,83 @@
+package sdk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCoin(t *testing.T) {
+	cases := []struct {
+		input string
+		wei   string
+		valid bool
+	}{
+		{"1000cmt", "1000000000000000000000", true},
+		{"1.5cmt", "1500000000000000000", true},
+		{"1.5 CMT", "1500000000000000000", true},
+		{"0.000000000000000001cmt", "1", true},
+		{"500wei", "500", true},
+		{"0x1f4", "500", true},
+		{"0cmt", "0", true},
+		{"0", "0", true},
+		{"1000", "", false},
+		{"1.5", "", false},
+		{"1e18", "", false},
+		{"0.0000000000000000001cmt", "", false},
+		{"1.5wei", "", false},
+		{"-1cmt", "", false},
+		{".5cmt", "", false},
+		{"1.cmt", "", false},
+		{"1cmtwei", "", false},
+		{"0x", "", false},
+		{"", "", false},
+	}
+
+	for _, tc := range cases {
+		c, err := ParseCoin(tc.input)
+		if !tc.valid {
+			assert.NotNil(t, err, tc.input)
+			continue
+		}
+		if assert.Nil(t, err, tc.input) {
+			assert.Equal(t, tc.wei, c.Wei(), tc.input)
+		}
+	}
+}
+
+func TestCoinString(t *testing.T) {
+	cases := []struct {
+		wei  string
+		text string
+	}{
+		{"1000000000000000000000", "1000cmt"},
+		{"1500000000000000000", "1.5cmt"},
+		{"1", "0.000000000000000001cmt"},
+		{"0", "0cmt"},
+	}
+
+	for _, tc := range cases {
+		c, ok := NewCoinFromWei(tc.wei)
+		assert.True(t, ok)
+		assert.Equal(t, tc.text, c.String())
+
+		// the printed amount is parsed back to the same one
+		parsed, err := ParseCoin(c.String())
+		assert.Nil(t, err)
+		assert.Equal(t, tc.wei, parsed.Wei())
+	}
+}
+
+func TestCoinJSON(t *testing.T) {
+	var v struct {
+		Amount Coin `json:"amount"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(`{"amount":"2.5cmt"}`), &v))
+	assert.Equal(t, "2500000000000000000", v.Amount.Wei())
+	assert.NotNil(t, json.Unmarshal([]byte(`{"amount":"2500"}`), &v))
+
+	b, err := json.Marshal(v)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"amount":"2.5cmt"}`, string(b))
+}
@@ -0,0 +1,178 @@
+package dbm
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Migration is a numbered change of the sqlite schema, the versions start at 1
+// and follow each other, a migration is never changed once released
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	// Applied tells whether a database created before the migrations were
+	// recorded has the change already, it is consulted if the migration is not recorded
+	Applied func(tx *sql.Tx) (bool, error)
+}
+
+// MigrationStatus reports whether a migration has been applied to the database
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt int64 // 0 if pending
+}
+
+const createSchemaMigrations = "create table if not exists schema_migrations(version integer not null primary key, name text not null, applied_at integer not null)"
+
+// Migrate applies the pending migrations in order, each in its own transaction,
+// and returns the applied ones
+func Migrate(db *sql.DB, migrations []Migration) ([]Migration, error) {
+	if err := checkMigrations(migrations); err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(createSchemaMigrations); err != nil {
+		return nil, err
+	}
+	if err := CheckSchemaVersion(db, migrations); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		ok, err := migrate(db, m)
+		if err != nil {
+			return applied, fmt.Errorf("migration %d (%s): %v", m.Version, m.Name, err)
+		}
+		if ok {
+			log.Info("Applied database migration", "version", m.Version, "name", m.Name)
+			applied = append(applied, m)
+		}
+	}
+	return applied, nil
+}
+
+// migrate applies and records the migration unless it has been applied already
+func migrate(db *sql.DB, m Migration) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var cnt int64
+	if err = tx.QueryRow("select count(*) from schema_migrations where version = ?", m.Version).Scan(&cnt); err != nil {
+		return false, err
+	}
+	if cnt > 0 {
+		return false, nil
+	}
+
+	exists := false
+	if m.Applied != nil {
+		if exists, err = m.Applied(tx); err != nil {
+			return false, err
+		}
+	}
+	if !exists {
+		if _, err = tx.Exec(m.Up); err != nil {
+			return false, err
+		}
+	}
+	if _, err = tx.Exec("insert into schema_migrations(version, name, applied_at) values(?, ?, ?)", m.Version, m.Name, time.Now().Unix()); err != nil {
+		return false, err
+	}
+	return !exists, tx.Commit()
+}
+
+// MigrationStatuses returns the status of every migration known to the program
+func MigrationStatuses(db *sql.DB, migrations []Migration) ([]MigrationStatus, error) {
+	recorded := make(map[int]int64)
+	exists, err := tableExists(db, "schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		rows, err := db.Query("select version, applied_at from schema_migrations")
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var version int
+			var appliedAt int64
+			if err = rows.Scan(&version, &appliedAt); err != nil {
+				return nil, err
+			}
+			recorded[version] = appliedAt
+		}
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, m := range migrations {
+		statuses[i] = MigrationStatus{m.Version, m.Name, recorded[m.Version]}
+	}
+	return statuses, nil
+}
+
+// SchemaVersion returns the version of the last migration applied to the database
+func SchemaVersion(db *sql.DB) (int, error) {
+	exists, err := tableExists(db, "schema_migrations")
+	if err != nil || !exists {
+		return 0, err
+	}
+	var version sql.NullInt64
+	if err = db.QueryRow("select max(version) from schema_migrations").Scan(&version); err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+// CheckSchemaVersion refuses a database migrated by a newer program
+func CheckSchemaVersion(db *sql.DB, migrations []Migration) error {
+	version, err := SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if latest := len(migrations); version > latest {
+		return fmt.Errorf("the database schema version %d is newer than the latest one %d known to this program, please upgrade it", version, latest)
+	}
+	return nil
+}
+
+func checkMigrations(migrations []Migration) error {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return fmt.Errorf("migration %s has version %d, want %d", m.Name, m.Version, i+1)
+		}
+	}
+	return nil
+}
+
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func tableExists(q queryer, table string) (bool, error) {
+	var cnt int64
+	err := q.QueryRow("select count(*) from sqlite_master where type = 'table' and name = ?", table).Scan(&cnt)
+	return cnt > 0, err
+}
+
+// TableExists tells whether the table exists, to probe the migrations applied before they were recorded
+func TableExists(tx *sql.Tx, table string) (bool, error) {
+	return tableExists(tx, table)
+}
+
+// ColumnExists tells whether the table has the column, to probe the migrations applied before they were recorded
+func ColumnExists(tx *sql.Tx, table, column string) (bool, error) {
+	var cnt int64
+	err := tx.QueryRow("select count(*) from pragma_table_info(?) where name = ?", table, column).Scan(&cnt)
+	return cnt > 0, err
+}
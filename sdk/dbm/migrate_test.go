@@ -0,0 +1,79 @@
+package dbm
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrate(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "travis-migrate")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "travis.db"))
+	assert.Nil(err)
+	defer db.Close()
+
+	// a database created before the migrations were recorded
+	_, err = db.Exec("create table foo(id integer not null primary key)")
+	assert.Nil(err)
+
+	migrations := []Migration{
+		{
+			Version: 1,
+			Name:    "create_foo",
+			Up:      "create table foo(id integer not null primary key)",
+			Applied: func(tx *sql.Tx) (bool, error) {
+				return TableExists(tx, "foo")
+			},
+		},
+		{
+			Version: 2,
+			Name:    "add_foo_name",
+			Up:      "alter table foo add column name text not null default ''",
+			Applied: func(tx *sql.Tx) (bool, error) {
+				return ColumnExists(tx, "foo", "name")
+			},
+		},
+	}
+
+	applied, err := Migrate(db, migrations)
+	assert.Nil(err)
+	assert.Equal(1, len(applied))
+	assert.Equal("add_foo_name", applied[0].Name)
+
+	version, err := SchemaVersion(db)
+	assert.Nil(err)
+	assert.Equal(2, version)
+
+	applied, err = Migrate(db, migrations)
+	assert.Nil(err)
+	assert.Equal(0, len(applied))
+
+	// a failed migration leaves nothing behind
+	migrations = append(migrations, Migration{
+		Version: 3,
+		Name:    "create_bar",
+		Up:      "create table bar(id integer not null primary key); insert into missing values(1);",
+	})
+	_, err = Migrate(db, migrations)
+	assert.NotNil(err)
+	statuses, err := MigrationStatuses(db, migrations)
+	assert.Nil(err)
+	assert.Equal(int64(0), statuses[2].AppliedAt)
+	exists, err := tableExists(db, "bar")
+	assert.Nil(err)
+	assert.False(exists)
+
+	// a database migrated by a newer program is refused
+	_, err = Migrate(db, migrations[:1])
+	assert.NotNil(err)
+}
@@ -11,4 +11,10 @@ const (
 
 	CodeTypeBaseInvalidInput  uint32 = 20
 	CodeTypeBaseInvalidOutput uint32 = 21
+
+	CodeLowGasPriceErr   uint32 = 101
+	CodeHighGasLimitErr  uint32 = 102
+	CodeLowPriceTxCapErr uint32 = 103
+	CodeLowGasLimitErr   uint32 = 104
+	CodeHighGasPriceErr  uint32 = 105
 )
@@ -15,6 +15,9 @@ var (
 	errInvalidFormat    = fmt.Errorf("Invalid format")
 	errUnknownModule    = fmt.Errorf("Unknown module")
 	errUnknownKey       = fmt.Errorf("Unknown key")
+	errLowGasPrice      = fmt.Errorf("The gas price is too low for transaction")
+	errLowGasLimit      = fmt.Errorf("The gas limit is too low for transaction")
+	errHighGasPrice     = fmt.Errorf("The gas price is too high for transaction")
 )
 
 // some crazy reflection to unwrap any generated struct.
@@ -60,6 +63,28 @@ func IsUnknownKeyErr(err error) bool {
 	return IsSameError(errUnknownKey, err)
 }
 
+func ErrLowGasPrice() TMError {
+	return WithCode(errLowGasPrice, CodeLowGasPriceErr)
+}
+func IsLowGasPriceErr(err error) bool {
+	return IsSameError(errLowGasPrice, err)
+}
+
+func ErrLowGasLimit(required uint64) TMError {
+	msg := fmt.Sprintf("required %d", required)
+	return WithMessage(msg, errLowGasLimit, CodeLowGasLimitErr)
+}
+func IsLowGasLimitErr(err error) bool {
+	return IsSameError(errLowGasLimit, err)
+}
+
+func ErrHighGasPrice() TMError {
+	return WithCode(errHighGasPrice, CodeHighGasPriceErr)
+}
+func IsHighGasPriceErr(err error) bool {
+	return IsSameError(errHighGasPrice, err)
+}
+
 func ErrInternal(msg string) TMError {
 	return New(msg, CodeTypeInternalErr)
 }
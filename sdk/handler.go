@@ -60,20 +60,28 @@ type DeliverResult struct {
 	GasFee  *big.Int
 }
 
+// GasFeeTag is the tag holding the gas fee of the tx in decimal, the fee of a
+// declared gas price may not fit in the int64 of the Fee
+const GasFeeTag = "GasFee"
+
 func (d DeliverResult) ToABCI() abci.ResponseDeliverTx {
 	var fee = common.KI64Pair{}
+	var tags []common.KVPair
 	if d.GasFee.Cmp(big.NewInt(0)) > 0 {
+		if d.GasFee.IsInt64() {
 			fee = common.KI64Pair{
-			Key: []byte("GasFee"),
-			Value: d.GasFee.Int64(),
+				Key:   []byte(GasFeeTag),
+				Value: d.GasFee.Int64(),
+			}
 		}
+		tags = append(tags, common.KVPair{Key: []byte(GasFeeTag), Value: []byte(d.GasFee.String())})
 	}
 	return abci.ResponseDeliverTx{
-		Data: d.Data,
-		Log:  d.Log,
-		Tags: nil,
+		Data:    d.Data,
+		Log:     d.Log,
+		Tags:    tags,
 		GasUsed: d.GasUsed,
-		Fee:	fee,
+		Fee:     fee,
 	}
 }
 
@@ -0,0 +1,172 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/tendermint/iavl"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// AppHash is the app hash of a block, it commits to the vm state, the iavl
+// store and the sqlite db
+func AppHash(vmHash, storeHash, dbHash []byte) []byte {
+	hasher := ripemd160.New()
+	buf := new(bytes.Buffer)
+	buf.Write(vmHash)
+	buf.Write(storeHash)
+	buf.Write(dbHash)
+	hasher.Write(buf.Bytes())
+	return hasher.Sum(nil)
+}
+
+// StateProof proves values of the iavl store against the app hash of the
+// block, the other parts of the app hash come along with the merkle proof
+type StateProof struct {
+	Height int64            `json:"height"`
+	Proof  *iavl.RangeProof `json:"proof"`
+	VMHash []byte           `json:"vm_hash"`
+	DbHash []byte           `json:"db_hash"`
+}
+
+// Verify checks the proof leads to the app hash, which is found in the header
+// of the block following the one at the height of the proof
+func (p *StateProof) Verify(appHash []byte) error {
+	if p.Proof == nil {
+		return fmt.Errorf("no merkle proof")
+	}
+	root := p.Proof.ComputeRootHash()
+	if !bytes.Equal(AppHash(p.VMHash, root, p.DbHash), appHash) {
+		return fmt.Errorf("the proof doesn't lead to the app hash %X", appHash)
+	}
+	return p.Proof.Verify(root)
+}
+
+// VerifyItem checks the key has the value, Verify must be called first
+func (p *StateProof) VerifyItem(key, value []byte) error {
+	return p.Proof.VerifyItem(key, value)
+}
+
+// VerifyAbsence checks the key has no value, Verify must be called first
+func (p *StateProof) VerifyAbsence(key []byte) error {
+	return p.Proof.VerifyAbsence(key)
+}
+
+// VerifyRange checks the values are the ones of all the keys with the prefix,
+// in the order of the keys, Verify must be called first
+func (p *StateProof) VerifyRange(prefix []byte, values [][]byte) error {
+	// no key is left out at either end of the range
+	if err := p.Proof.VerifyAbsence(prefix); err != nil {
+		return err
+	}
+	if end := PrefixEnd(prefix); end != nil {
+		if err := p.Proof.VerifyAbsence(end); err != nil {
+			return err
+		}
+	}
+
+	var keys [][]byte
+	for _, leaf := range p.Proof.Leaves {
+		if bytes.HasPrefix(leaf.Key, prefix) {
+			keys = append(keys, leaf.Key)
+		}
+	}
+	if len(keys) != len(values) {
+		return fmt.Errorf("%d values for the %d keys of the range", len(values), len(keys))
+	}
+	for i, key := range keys {
+		if err := p.Proof.VerifyItem(key, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Mirror keeps rows of the sqlite db in the iavl store under a prefix, so
+// they are committed by the app hash one by one and can be proven. The rows
+// are touched where they are written, only those are copied at the commit.
+type Mirror struct {
+	prefix  []byte
+	mtx     sync.Mutex
+	touched map[string]struct{}
+}
+
+func NewMirror(prefix []byte) *Mirror {
+	return &Mirror{prefix: prefix, touched: make(map[string]struct{})}
+}
+
+// Touch marks the value of the key as changed, the key must start with the
+// prefix of the mirror
+func (m *Mirror) Touch(key []byte) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.touched[string(key)] = struct{}{}
+}
+
+// Update copies the values of the keys touched since the last update, load
+// returns nil for a key whose row is gone
+func (m *Mirror) Update(store SimpleDB, load func(key []byte) []byte) {
+	m.mtx.Lock()
+	keys := make([]string, 0, len(m.touched))
+	for k := range m.touched {
+		keys = append(keys, k)
+	}
+	m.touched = make(map[string]struct{})
+	m.mtx.Unlock()
+
+	// the shape of the tree depends on the order of the writes
+	sort.Strings(keys)
+	for _, k := range keys {
+		m.set(store, []byte(k), load([]byte(k)))
+	}
+}
+
+// Seed copies all the values and removes the keys missing from them, it's
+// called when the mirror starts
+func (m *Mirror) Seed(store SimpleDB, values map[string][]byte) {
+	m.mtx.Lock()
+	m.touched = make(map[string]struct{})
+	m.mtx.Unlock()
+
+	for _, model := range store.List(m.prefix, PrefixEnd(m.prefix), 0) {
+		if _, ok := values[string(model.Key)]; !ok {
+			store.Remove(model.Key)
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		m.set(store, []byte(k), values[k])
+	}
+}
+
+// set writes the value only if it changed, as any write changes the version of the leaf
+func (m *Mirror) set(store SimpleDB, key, value []byte) {
+	if value == nil {
+		if store.Has(key) {
+			store.Remove(key)
+		}
+		return
+	}
+	if !bytes.Equal(store.Get(key), value) {
+		store.Set(key, value)
+	}
+}
+
+// PrefixEnd returns the first key after all the keys with the prefix
+func PrefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/iavl"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+func TestMirror(t *testing.T) {
+	assert := assert.New(t)
+
+	prefix := []byte{0x07}
+	key := func(k string) []byte {
+		return append([]byte{0x07}, k...)
+	}
+	rows := map[string][]byte{
+		string(key("a")): []byte("1"),
+		string(key("b")): []byte("2"),
+	}
+	load := func(k []byte) []byte {
+		return rows[string(k)]
+	}
+
+	store := NewMemKVStore()
+	store.Set([]byte{0x06}, []byte("other"))
+	store.Set(key("gone"), []byte("0"))
+
+	m := NewMirror(prefix)
+	m.Touch(key("a"))
+	m.Seed(store, rows)
+	assert.Equal([]byte("1"), store.Get(key("a")))
+	assert.Equal([]byte("2"), store.Get(key("b")))
+	assert.False(store.Has(key("gone")))
+	assert.Equal([]byte("other"), store.Get([]byte{0x06}))
+
+	// add
+	rows[string(key("c"))] = []byte("3")
+	m.Touch(key("c"))
+	m.Update(store, load)
+	assert.Equal([]byte("3"), store.Get(key("c")))
+
+	// update
+	rows[string(key("a"))] = []byte("4")
+	m.Touch(key("a"))
+	m.Update(store, load)
+	assert.Equal([]byte("4"), store.Get(key("a")))
+
+	// remove
+	delete(rows, string(key("b")))
+	m.Touch(key("b"))
+	m.Update(store, load)
+	assert.False(store.Has(key("b")))
+
+	// only the touched rows are copied
+	rows[string(key("c"))] = []byte("5")
+	m.Update(store, load)
+	assert.Equal([]byte("3"), store.Get(key("c")))
+	assert.Equal([]byte("other"), store.Get([]byte{0x06}))
+}
+
+func TestStateProofVerifyRange(t *testing.T) {
+	assert, require := assert.New(t), require.New(t)
+
+	prefix := []byte{0x07}
+	store := NewBonsai(iavl.NewVersionedTree(dbm.NewMemDB(), 0))
+	store.Set([]byte{0x06, 0x01}, []byte("before"))
+	store.Set([]byte{0x07, 0x01}, []byte("a"))
+	store.Set([]byte{0x07, 0x02}, []byte("b"))
+	store.Set([]byte{0x07, 0x03}, []byte("c"))
+	store.Set([]byte{0x08, 0x01}, []byte("after"))
+	root, version, err := store.Tree.SaveVersion()
+	require.Nil(err)
+
+	vmHash, dbHash := []byte("vm"), []byte("db")
+	appHash := AppHash(vmHash, root, dbHash)
+	proofOf := func(start, end []byte, limit int) *StateProof {
+		_, _, proof, err := store.Tree.GetVersionedRangeWithProof(start, end, limit, version)
+		require.Nil(err)
+		p := &StateProof{Height: version, Proof: proof, VMHash: vmHash, DbHash: dbHash}
+		require.Nil(p.Verify(appHash))
+		return p
+	}
+	values := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+
+	p := proofOf(prefix, PrefixEnd(prefix), 0)
+	assert.Nil(p.VerifyRange(prefix, values))
+	assert.NotNil(p.Verify([]byte("other app hash")))
+
+	// a tampered value is rejected
+	assert.NotNil(p.VerifyRange(prefix, [][]byte{[]byte("a"), []byte("tampered"), []byte("c")}))
+	// so is a value left out
+	assert.NotNil(p.VerifyRange(prefix, values[:2]))
+
+	// the proof must cover the first key of the range
+	p = proofOf([]byte{0x07, 0x02}, PrefixEnd(prefix), 0)
+	assert.NotNil(p.VerifyRange(prefix, values[1:]))
+
+	// and the last one
+	p = proofOf(prefix, PrefixEnd(prefix), 2)
+	assert.NotNil(p.VerifyRange(prefix, values[:2]))
+}
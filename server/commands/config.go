@@ -26,6 +26,9 @@ type TravisConfig struct {
 	BaseConfig BaseConfig      `mapstructure:",squash"`
 	TMConfig   tmcfg.Config    `mapstructure:",squash"`
 	EMConfig   EthermintConfig `mapstructure:"vm"`
+	Metrics    MetricsConfig   `mapstructure:"metrics"`
+	Health     HealthConfig    `mapstructure:"health"`
+	Signer     SignerConfig    `mapstructure:"signer"`
 }
 
 func DefaultConfig() *TravisConfig {
@@ -33,6 +36,9 @@ func DefaultConfig() *TravisConfig {
 		BaseConfig: DefaultBaseConfig(),
 		TMConfig:   *tmcfg.DefaultConfig(),
 		EMConfig:   DefaultEthermintConfig(),
+		Metrics:    DefaultMetricsConfig(),
+		Health:     DefaultHealthConfig(),
+		Signer:     DefaultSignerConfig(),
 	}
 }
 
@@ -91,6 +97,46 @@ func DefaultEthermintConfig() EthermintConfig {
 	}
 }
 
+// MetricsConfig is the prometheus endpoint of the staking, the awards and the governance
+type MetricsConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	ListenAddr string `mapstructure:"laddr"`
+}
+
+func DefaultMetricsConfig() MetricsConfig {
+	return MetricsConfig{
+		Enabled:    false,
+		ListenAddr: "0.0.0.0:26661",
+	}
+}
+
+// HealthConfig is the /health and /ready endpoint of the load balancers
+type HealthConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	ListenAddr    string `mapstructure:"laddr"`
+	MinFreeDiskMB uint64 `mapstructure:"min_free_disk_mb"`
+}
+
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		Enabled:       false,
+		ListenAddr:    "0.0.0.0:26662",
+		MinFreeDiskMB: 1024,
+	}
+}
+
+// SignerConfig is the external signer the cmt_* methods sign with for the
+// accounts which are not in the keystore
+type SignerConfig struct {
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+func DefaultSignerConfig() SignerConfig {
+	return SignerConfig{
+		Endpoint: "",
+	}
+}
+
 // copied from tendermint/commands/root.go
 // to call our revised EnsureRoot
 func ParseConfig() (*TravisConfig, error) {
@@ -174,6 +220,7 @@ func AppendVMConfig(configFilePath string, conf *TravisConfig) {
 var defaultVmTemplate = `
 [vm]
 chainid = {{ .EMConfig.ChainId }}
+abci_laddr = "{{ .EMConfig.ABCIAddr }}"
 rpc = {{ .EMConfig.RPCEnabledFlag }}
 rpcapi = "{{ .EMConfig.RPCApiFlag }}"
 rpcaddr = "{{ .EMConfig.RPCListenAddrFlag }}"
@@ -181,6 +228,21 @@ rpcport = {{ .EMConfig.RPCPortFlag }}
 rpccorsdomain = "{{ .EMConfig.RPCCORSDomainFlag }}"
 rpcvhosts = "{{ .EMConfig.RPCVirtualHostsFlag }}"
 ws = {{ .EMConfig.WSEnabledFlag }}
+wsport = {{ .EMConfig.WSPortFlag }}
 ipcdisable = {{ .EMConfig.IPCDisabledFlag }}
+listenport = {{ .EMConfig.ListenPortFlag }}
 verbosity = "{{ .EMConfig.VerbosityFlag }}"
+
+[metrics]
+enabled = {{ .Metrics.Enabled }}
+laddr = "{{ .Metrics.ListenAddr }}"
+
+[health]
+enabled = {{ .Health.Enabled }}
+laddr = "{{ .Health.ListenAddr }}"
+min_free_disk_mb = {{ .Health.MinFreeDiskMB }}
+
+[signer]
+# url or local socket of a clef compatible signer, empty for the keystore only
+endpoint = "{{ .Signer.Endpoint }}"
 `
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tendermint/libs/cli"
+
+	"github.com/CyberMiles/travis/sdk/dbm"
+)
+
+// DbCmd manages the schema of the travis database
+var DbCmd = GetDbCmd()
+
+func GetDbCmd() *cobra.Command {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage the travis database",
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Help() },
+	}
+	dbCmd.AddCommand(
+		&cobra.Command{
+			Use:   "migrate",
+			Short: "Apply the pending database migrations",
+			RunE:  dbMigrate,
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "Show the database migrations and whether they are applied",
+			RunE:  dbStatus,
+		},
+	)
+	return dbCmd
+}
+
+func dbMigrate(cmd *cobra.Command, args []string) error {
+	db, err := openTravisDb(viper.GetString(cli.HomeFlag))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	applied, err := dbm.Migrate(db, migrations)
+	for _, m := range applied {
+		fmt.Printf("Applied %d %s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("The database is up to date")
+	}
+	return nil
+}
+
+func dbStatus(cmd *cobra.Command, args []string) error {
+	db, err := openTravisDb(viper.GetString(cli.HomeFlag))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	version, err := dbm.SchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	statuses, err := dbm.MigrationStatuses(db, migrations)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Schema version: %d, latest: %d\n", version, len(migrations))
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range statuses {
+		appliedAt := "pending"
+		if s.AppliedAt > 0 {
+			appliedAt = time.Unix(s.AppliedAt, 0).UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+	}
+	w.Flush()
+
+	return dbm.CheckSchemaVersion(db, migrations)
+}
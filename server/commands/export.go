@@ -0,0 +1,217 @@
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/tendermint/tendermint/libs/cli"
+
+	"github.com/CyberMiles/travis/app"
+	"github.com/CyberMiles/travis/modules/governance"
+	"github.com/CyberMiles/travis/modules/multisig"
+	"github.com/CyberMiles/travis/modules/sponsor"
+	"github.com/CyberMiles/travis/modules/stake"
+	"github.com/CyberMiles/travis/sdk/dbm"
+	sm "github.com/CyberMiles/travis/sdk/state"
+	"github.com/CyberMiles/travis/types"
+	"github.com/CyberMiles/travis/utils"
+	emtUtils "github.com/CyberMiles/travis/vm/cmd/utils"
+)
+
+const FlagOutput = "output"
+
+// ExportCmd writes the state of a stopped node as the genesis files of a new chain
+var ExportCmd = GetExportCmd()
+
+func GetExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the state of the stopped node as the genesis of a new chain",
+		Long: `Export the state of the stopped node as the genesis of a new chain.
+
+Only the last committed height can be exported, the sql db of the stake and the
+governance keeps no history. The stake, the undecided proposals, the sponsorships and the multisig accounts are
+written to genesis.json, the accounts and the contracts to vm-genesis.json. The new
+chain is initialized with
+  travis node init --vm-genesis vm-genesis.json
+after genesis.json is copied to the config directory.`,
+		RunE: exportCmd,
+	}
+	exportCmd.Flags().String(FlagChainID, "", "Chain ID of the new chain, defaults to the one of the exported chain")
+	exportCmd.Flags().String(FlagOutput, ".", "Directory the genesis files are written to")
+	return exportCmd
+}
+
+func exportCmd(cmd *cobra.Command, args []string) error {
+	rootDir := viper.GetString(cli.HomeFlag)
+	if err := dbm.InitSqliter(path.Join(rootDir, "data", utils.DB_FILE_NAME)); err != nil {
+		return err
+	}
+	defer dbm.Sqliter.CloseDB()
+
+	// fails if the node is running, the database is locked
+	storeApp, err := app.NewStoreApp("export", path.Join(rootDir, "data", "merkleeyes.db"), EyesCacheSize, logger.With("module", "app"))
+	if err != nil {
+		return errors.Errorf("Error in opening the state, is the node stopped? %v\n", err)
+	}
+	height := storeApp.CommittedHeight()
+	store := storeApp.Append()
+	if b := store.Get(utils.ParamKey); b != nil {
+		utils.LoadParams(b)
+	}
+
+	genDoc, err := loadGenesis(path.Join(rootDir, DefaultConfig().TMConfig.GenesisFile()))
+	if err != nil {
+		return errors.Errorf("Error in LoadGenesis: %v\n", err)
+	}
+	if err = exportGenesis(genDoc, store, height); err != nil {
+		return err
+	}
+	if chainID := viper.GetString(FlagChainID); chainID != "" {
+		genDoc.ChainID = chainID
+	}
+
+	vmGenesis, err := exportVmGenesis(height)
+	if err != nil {
+		return err
+	}
+
+	output := viper.GetString(FlagOutput)
+	if err = os.MkdirAll(output, 0755); err != nil {
+		return err
+	}
+	if err = genDoc.SaveAs(filepath.Join(output, "genesis.json")); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(vmGenesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(filepath.Join(output, "vm-genesis.json"), b, 0644); err != nil {
+		return err
+	}
+
+	logger.Info("Exported the state", "height", height, "candidates", len(genDoc.Candidates),
+		"proposals", len(genDoc.Proposals), "accounts", len(vmGenesis.Alloc), "output", output)
+	return nil
+}
+
+// exportGenesis replaces the initial stake of the genesis with the one at the height
+func exportGenesis(genDoc *types.GenesisDoc, store sm.SimpleDB, height int64) error {
+	genDoc.GenesisTime = time.Now()
+	genDoc.Params = utils.GetParams()
+	genDoc.ExportedHeight = height
+	genDoc.Candidates = nil
+	genDoc.Delegations = nil
+	genDoc.UnstakeRequests = nil
+	genDoc.Proposals = nil
+	genDoc.Sponsorships = nil
+	genDoc.Multisigs = nil
+	genDoc.AbsentValidators = nil
+
+	stake.ExportGenesis(store, genDoc, height)
+	governance.ExportGenesis(genDoc, height)
+	sponsor.ExportGenesis(store, genDoc)
+	multisig.ExportGenesis(store, genDoc)
+	if !genDoc.IsExported() {
+		return errors.New("No active candidate to export")
+	}
+	return nil
+}
+
+// importGenesis restores the state exported by exportGenesis
+func importGenesis(genDoc *types.GenesisDoc, store sm.SimpleDB) error {
+	if err := stake.ImportGenesis(store, genDoc); err != nil {
+		return err
+	}
+	if err := governance.ImportGenesis(genDoc); err != nil {
+		return err
+	}
+	if err := sponsor.ImportGenesis(store, genDoc); err != nil {
+		return err
+	}
+	return multisig.ImportGenesis(store, genDoc)
+}
+
+// exportVmGenesis dumps the accounts of the vm state at the height, the node
+// must have recorded the preimages of the trie keys
+func exportVmGenesis(height int64) (*core.Genesis, error) {
+	chainDb, err := ethdb.NewLDBDatabase(filepath.Join(emtUtils.MakeDataDir(context), "vm/chaindata"), 0, 0)
+	if err != nil {
+		return nil, errors.Errorf("could not open database: %v", err)
+	}
+	defer chainDb.Close()
+
+	headHash := rawdb.ReadHeadBlockHash(chainDb)
+	number := rawdb.ReadHeaderNumber(chainDb, headHash)
+	if number == nil || int64(*number) != height {
+		return nil, errors.Errorf("The vm state is not at the height %d", height)
+	}
+	head := rawdb.ReadBlock(chainDb, headHash, *number)
+	genesisHash := rawdb.ReadCanonicalHash(chainDb, 0)
+	genesis := rawdb.ReadBlock(chainDb, genesisHash, 0)
+	if head == nil || genesis == nil {
+		return nil, errors.New("The vm blocks are missing")
+	}
+
+	statedb, err := state.New(head.Root(), state.NewDatabase(chainDb))
+	if err != nil {
+		return nil, err
+	}
+	dump := statedb.RawDump()
+
+	alloc := make(core.GenesisAlloc, len(dump.Accounts))
+	for addr, account := range dump.Accounts {
+		if addr == "" {
+			return nil, errors.New("The address of an account is unknown, the preimages have not been recorded")
+		}
+		balance, ok := new(big.Int).SetString(account.Balance, 10)
+		if !ok {
+			return nil, errors.Errorf("Invalid balance of %s", addr)
+		}
+		ga := core.GenesisAccount{
+			Code:    common.FromHex(account.Code),
+			Balance: balance,
+			Nonce:   account.Nonce,
+		}
+		if len(account.Storage) > 0 {
+			ga.Storage = make(map[common.Hash]common.Hash, len(account.Storage))
+		}
+		for key, value := range account.Storage {
+			if key == "" {
+				return nil, errors.Errorf("A storage key of %s is unknown, the preimages have not been recorded", addr)
+			}
+			// the values are rlp encoded in the trie
+			_, content, _, err := rlp.Split(common.FromHex(value))
+			if err != nil {
+				return nil, errors.Errorf("Invalid storage value of %s: %v", addr, err)
+			}
+			ga.Storage[common.HexToHash(key)] = common.BytesToHash(content)
+		}
+		alloc[common.HexToAddress(addr)] = ga
+	}
+
+	return &core.Genesis{
+		Config:     rawdb.ReadChainConfig(chainDb, genesisHash),
+		Timestamp:  uint64(time.Now().Unix()),
+		ExtraData:  genesis.Extra(),
+		GasLimit:   genesis.GasLimit(),
+		Difficulty: genesis.Difficulty(),
+		Alloc:      alloc,
+	}, nil
+}
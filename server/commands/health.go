@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/CyberMiles/travis/api"
+	"github.com/CyberMiles/travis/app"
+	"github.com/CyberMiles/travis/sdk/dbm"
+	"github.com/CyberMiles/travis/server"
+)
+
+// number of the recent blocks the validator of the node is expected to sign one of
+const healthSignedBlocks = 10
+
+// healthCheck is the result of one of the checks
+type healthCheck struct {
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+type healthResult struct {
+	OK     bool                   `json:"ok"`
+	Height int64                  `json:"height"`
+	Checks map[string]healthCheck `json:"checks"`
+}
+
+// healthChecker answers the load balancers and the monitors of the operators
+type healthChecker struct {
+	backend     *api.Backend
+	app         *app.BaseApp
+	dataDir     string
+	minFreeDisk uint64
+}
+
+// startHealth serves /health, whether the node works, and /ready, whether it
+// works and has caught up with the chain so it can serve the rpc
+func startHealth(conf HealthConfig, rootDir string, backend *api.Backend, baseApp *app.BaseApp) error {
+	h := &healthChecker{backend, baseApp, rootDir, conf.MinFreeDiskMB * 1024 * 1024}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, h.health(false))
+	})
+	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, h.health(true))
+	})
+
+	l, err := net.Listen("tcp", conf.ListenAddr)
+	if err != nil {
+		return err
+	}
+	go http.Serve(l, mux)
+	return nil
+}
+
+func writeHealth(w http.ResponseWriter, res *healthResult) {
+	w.Header().Set("Content-Type", "application/json")
+	if !res.OK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *healthChecker) health(ready bool) *healthResult {
+	res := &healthResult{OK: true, Checks: make(map[string]healthCheck)}
+	add := func(name string, ok bool, format string, args ...interface{}) {
+		res.Checks[name] = healthCheck{ok, fmt.Sprintf(format, args...)}
+		res.OK = res.OK && ok
+	}
+
+	if err := h.checkSqlite(); err != nil {
+		add("sqlite", false, "%v", err)
+	} else {
+		add("sqlite", true, "connected")
+	}
+
+	if free, err := freeDisk(h.dataDir); err != nil {
+		add("disk", false, "%v", err)
+	} else {
+		add("disk", free >= h.minFreeDisk, "%d MB free in %s", free/1024/1024, h.dataDir)
+	}
+
+	status, err := h.backend.GetLocalClient().Status()
+	if err != nil {
+		add("tendermint", false, "%v", err)
+		return res
+	}
+	height := status.SyncInfo.LatestBlockHeight
+	res.Height = height
+
+	ok, detail := h.checkAppHash(height)
+	add("app_hash", ok, "%s", detail)
+
+	if status.ValidatorInfo.VotingPower == 0 {
+		add("validator", true, "not a validator")
+	} else {
+		signed := h.signedBlocks(status.ValidatorInfo.Address, height)
+		add("validator", signed > 0, "signed %d of the last %d blocks", signed, healthSignedBlocks)
+	}
+
+	if ready {
+		add("shutting_down", !server.ShuttingDown(), "shutting down: %v", server.ShuttingDown())
+		add("catching_up", !status.SyncInfo.CatchingUp, "catching up: %v", status.SyncInfo.CatchingUp)
+
+		head := int64(h.backend.Ethereum().BlockChain().CurrentBlock().NumberU64())
+		// the vm block of the block being committed may be written already
+		add("vm_head", head == height || head == height+1, "vm head %d, committed height %d", head, height)
+	}
+	return res
+}
+
+func (h *healthChecker) checkSqlite() error {
+	db, err := dbm.Sqliter.GetDB()
+	if err != nil {
+		return err
+	}
+	return db.Ping()
+}
+
+// checkAppHash compares the app hash of the node with the one in the header of
+// the last block, it is the one the validators agreed on for the previous height
+func (h *healthChecker) checkAppHash(height int64) (bool, string) {
+	if height < 2 {
+		return true, "no block to compare with"
+	}
+	block, err := h.backend.GetLocalClient().Block(&height)
+	if err != nil {
+		return false, err.Error()
+	}
+	own, ok := h.app.CommittedAppHash(height - 1)
+	if !ok {
+		return true, "app hash of the previous block unknown since the restart"
+	}
+	if !bytes.Equal(own, block.Block.AppHash) {
+		return false, fmt.Sprintf("app hash at height %d is %X, the validators agreed on %X", height-1, own, []byte(block.Block.AppHash))
+	}
+	return true, fmt.Sprintf("%X at height %d", own, height-1)
+}
+
+// signedBlocks counts the recent blocks the validator precommitted
+func (h *healthChecker) signedBlocks(address []byte, height int64) (signed int) {
+	for i := int64(0); i < healthSignedBlocks && height-i > 0; i++ {
+		hi := height - i
+		commit, err := h.backend.GetLocalClient().Commit(&hi)
+		if err != nil {
+			continue
+		}
+		for _, vote := range commit.Commit.Precommits {
+			if vote != nil && bytes.Equal(vote.ValidatorAddress, address) {
+				signed++
+				break
+			}
+		}
+	}
+	return
+}
+
+func freeDisk(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
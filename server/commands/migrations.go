@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"database/sql"
+	"path/filepath"
+
+	"github.com/CyberMiles/travis/sdk/dbm"
+	travisUtils "github.com/CyberMiles/travis/utils"
+)
+
+// migrations evolve the travis database, append new ones at the end and never change a released one
+var migrations = []dbm.Migration{
+	{
+		Version: 1,
+		Name:    "create_tables",
+		Up: `
+	create table candidates(id integer not null primary key autoincrement, address text not null, pub_key text not null, shares text not null default '0', voting_power integer default 0, pending_voting_power integer default 0, max_shares text not null default '0', comp_rate text not null default '0', name text not null default '', website text not null default '', location text not null default '', email text not null default '', profile text not null default '', verified text not null default 'N', active text not null default 'Y', rank integer not null default 0, state text not null default '', hash text not null default '', block_height integer not null, num_of_delegators integer not null default 0, created_at integer not null);
+	create unique index idx_candidates_pub_key on candidates(pub_key);
+	create unique index idx_candidates_address on candidates(address);
+	create index idx_candidates_hash on candidates(hash);
+	create table delegations(id integer not null primary key autoincrement, delegator_address text not null, candidate_id integer not null, delegate_amount text not null default '0', award_amount text not null default '0', withdraw_amount text not null default '0', pending_withdraw_amount text not null default '0', slash_amount text not null default '0', comp_rate text not null default '0', hash text not null default '',  voting_power integer not null default 0, state text not null default 'Y', block_height integer not null, average_staking_date integer not null default 0, created_at integer not null);
+	create unique index idx_delegations_delegator_address_candidate_id on delegations(delegator_address, candidate_id);
+	create index idx_delegations_hash on delegations(hash);
+ 	create table delegate_history(id integer not null primary key autoincrement, delegator_address text not null, candidate_id integer not null, amount text not null default '0', op_code text not null default '', block_height integer not null, hash text not null default '');
+	create index idx_delegate_history_delegator_address on delegate_history(delegator_address);
+	create index idx_delegate_history_candidate_id on delegate_history(candidate_id);
+	create index idx_delegate_history_hash on delegate_history(hash);
+	create table slashes(id integer not null primary key autoincrement, candidate_id integer not null, slash_ratio integer default 0, slash_amount text not null, reason text not null default '', created_at integer not null, block_height integer not null, hash text not null default '');
+	create index idx_slashes_candidate_id on slashes(candidate_id);
+	create index idx_slashes_hash on slashes(hash);
+ 	create table unstake_requests(id integer not null primary key autoincrement, delegator_address text not null, candidate_id integer not null, initiated_block_height integer default 0, performed_block_height integer default 0, amount text not null default '0', state text not null default 'PENDING', hash text not null default '');
+ 	create index idx_unstake_requests_delegator_address on unstake_requests(delegator_address);
+ 	create table candidate_daily_stakes(id integer not null primary key autoincrement, candidate_id integer not null, amount text not null default '0', block_height integer not null, hash text not null default '');
+	create index idx_candidate_daily_stakes_candidate_id on candidate_daily_stakes(candidate_id);
+	create index idx_candidate_daily_stakes_hash on candidate_daily_stakes(hash);
+	create table candidate_account_update_requests(id integer primary key autoincrement, candidate_id integer not null, from_address text not null, to_address text not null, created_block_height integer not null, accepted_block_height integer not null, state text not null, hash text not null default '');
+	create index idx_candidate_account_update_requests_to_address on candidate_account_update_requests(to_address);
+	create index idx_candidate_account_update_requests_hash on candidate_account_update_requests(hash);
+
+ 	create table governance_proposal(id text not null primary key, type text not null, proposer text not null, block_height integer not null, expire_timestamp integer not null, expire_block_height integer not null, hash text not null default '', result text not null default '', result_msg text not null default '', result_block_height integer not null default 0);
+	create index idx_governance_proposal_hash on governance_proposal(hash);
+ 	create table governance_transfer_fund_detail(proposal_id text not null, from_address text not null, to_address text not null, amount text not null, reason text not null);
+	create index idx_governance_transfer_fund_detail_proposal_id on governance_transfer_fund_detail(proposal_id);
+ 	create table governance_change_param_detail(proposal_id text not null, param_name text not null, param_value text not null, reason text not null);
+	create index idx_governance_change_param_detail_proposal_id on governance_change_param_detail(proposal_id);
+	create table governance_deploy_libeni_detail(proposal_id text not null, name text not null, version text not null, fileurl text not null, md5 text not null, reason text not null, status text not null);
+	create index idx_governance_deploy_libeni_detail_proposal_id on governance_deploy_libeni_detail(proposal_id);
+	create table governance_retire_program_detail(proposal_id text not null, retired_version text not null, preserved_validators text not null, reason text not null, status text not null);
+	create index idx_governance_retire_program_detail_proposal_id on governance_retire_program_detail(proposal_id);
+	create table governance_upgrade_program_detail(proposal_id text not null, retired_version text not null, name text not null, version text not null, fileurl text not null, md5 text not null, reason text not null);
+	create index idx_governance_upgrade_program_detail_proposal_id on governance_retire_program_detail(proposal_id);
+ 	create table governance_vote(proposal_id text not null, voter text not null, block_height integer not null, answer text not null,  hash text not null default '', unique(proposal_id, voter) ON conflict replace);
+	create index idx_governance_vote_voter on governance_vote(voter);
+	create index idx_governance_vote_proposal_id on governance_vote(proposal_id);
+	create index idx_governance_vote_hash on governance_vote(hash);
+
+	`,
+		Applied: func(tx *sql.Tx) (bool, error) {
+			return dbm.TableExists(tx, "candidates")
+		},
+	},
+	{
+		Version: 2,
+		Name:    "add_delegations_source",
+		Up:      "alter table delegations add column source text not null default 'cube'",
+		Applied: func(tx *sql.Tx) (bool, error) {
+			return dbm.ColumnExists(tx, "delegations", "source")
+		},
+	},
+	{
+		Version: 3,
+		Name:    "add_delegations_completely_withdraw",
+		Up:      "alter table delegations add column completely_withdraw text not null default 'N'",
+		Applied: func(tx *sql.Tx) (bool, error) {
+			return dbm.ColumnExists(tx, "delegations", "completely_withdraw")
+		},
+	},
+	{
+		Version: 4,
+		Name:    "add_unstake_requests_actual_amount",
+		Up:      "alter table unstake_requests add column actual_amount text not null default '0'",
+		Applied: func(tx *sql.Tx) (bool, error) {
+			return dbm.ColumnExists(tx, "unstake_requests", "actual_amount")
+		},
+	},
+	{
+		Version: 5,
+		Name:    "create_validator_set_changes",
+		Up: `
+	create table validator_set_changes(id integer not null primary key autoincrement, candidate_id integer not null, address text not null, old_state text not null default '', new_state text not null default '', old_rank integer not null default 0, new_rank integer not null default 0, old_tendermint_voting_power integer not null default 0, new_tendermint_voting_power integer not null default 0, reason text not null default '', block_height integer not null);
+	create index idx_validator_set_changes_block_height on validator_set_changes(block_height);
+	create index idx_validator_set_changes_address on validator_set_changes(address);
+	`,
+		Applied: func(tx *sql.Tx) (bool, error) {
+			return dbm.TableExists(tx, "validator_set_changes")
+		},
+	},
+	{
+		Version: 6,
+		Name:    "create_governance_upgrade_ready",
+		Up: `
+	create table governance_upgrade_ready(proposal_id text not null, validator text not null, block_height integer not null, hash text not null default '', unique(proposal_id, validator) ON conflict replace);
+	create index idx_governance_upgrade_ready_proposal_id on governance_upgrade_ready(proposal_id);
+	`,
+		Applied: func(tx *sql.Tx) (bool, error) {
+			return dbm.TableExists(tx, "governance_upgrade_ready")
+		},
+	},
+	{
+		Version: 7,
+		Name:    "create_app_state",
+		Up:      "create table app_state(id integer not null primary key, block_height integer not null)",
+		Applied: func(tx *sql.Tx) (bool, error) {
+			return dbm.TableExists(tx, "app_state")
+		},
+	},
+	{
+		Version: 8,
+		Name:    "create_stake_query_indexes",
+		Up: `
+	create index if not exists idx_candidates_address on candidates(address);
+	create index if not exists idx_candidates_state on candidates(state);
+	create index if not exists idx_delegations_delegator_address on delegations(delegator_address);
+	create index if not exists idx_delegations_candidate_id on delegations(candidate_id);
+	`,
+	},
+}
+
+func openTravisDb(rootDir string) (*sql.DB, error) {
+	return sql.Open("sqlite3", filepath.Join(rootDir, "data", travisUtils.DB_FILE_NAME))
+}
+
+// migrateDatabase brings the travis database to the schema of this program
+func migrateDatabase(rootDir string) error {
+	db, err := openTravisDb(rootDir)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = dbm.Migrate(db, migrations)
+	return err
+}
@@ -1,9 +1,7 @@
 package commands
 
 import (
-	"database/sql"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"gopkg.in/urfave/cli.v1"
@@ -21,7 +19,7 @@ import (
 
 	"github.com/CyberMiles/travis/api"
 	"github.com/CyberMiles/travis/app"
-	travisUtils "github.com/CyberMiles/travis/utils"
+	"github.com/CyberMiles/travis/signer"
 	"github.com/CyberMiles/travis/vm/cmd/utils"
 	emtUtils "github.com/CyberMiles/travis/vm/cmd/utils"
 	"github.com/CyberMiles/travis/vm/ethereum"
@@ -31,6 +29,7 @@ type Services struct {
 	backend *api.Backend
 	tmNode  *node.Node
 	emNode  *ethereum.Node
+	app     *app.BaseApp
 }
 
 func startServices(rootDir string, storeApp *app.StoreApp) (*Services, error) {
@@ -58,18 +57,6 @@ func startServices(rootDir string, storeApp *app.StoreApp) (*Services, error) {
 	}
 	ethApp.SetLogger(emtUtils.EthermintLogger().With("module", "vm"))
 
-	// Alter database if needed
-	if err = alterDatabaseIfNeeded(rootDir); err != nil {
-		log.Warn(err.Error())
-		os.Exit(1)
-	}
-
-	// Alter database if needed
-	if err = alterDatabaseIfNeeded2(rootDir); err != nil {
-		log.Warn(err.Error())
-		os.Exit(1)
-	}
-
 	// Create Basecoin app
 	basecoinApp, err := createBaseApp(rootDir, storeApp, ethApp, backend.Ethereum())
 	if err != nil {
@@ -85,7 +72,21 @@ func startServices(rootDir string, storeApp *app.StoreApp) (*Services, error) {
 	}
 	backend.SetTMNode(tmNode)
 
-	return &Services{backend, tmNode, emNode}, nil
+	if config.Signer.Endpoint != "" {
+		ext, err := signer.NewExternal(config.Signer.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		backend.SetExternalSigner(ext)
+	}
+
+	if config.Health.Enabled {
+		if err = startHealth(config.Health, rootDir, backend, basecoinApp); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Services{backend, tmNode, emNode, basecoinApp}, nil
 }
 
 // startNode copies the logic from go-ethereum
@@ -175,80 +176,3 @@ func startTendermint(basecoinApp abcitypes.Application) (*node.Node, error) {
 
 	return n, nil
 }
-
-func alterDatabaseIfNeeded(rootDir string) error {
-	stakeDbPath := filepath.Join(rootDir, "data", travisUtils.DB_FILE_NAME)
-	db, err := sql.Open("sqlite3", stakeDbPath)
-	if err != nil {
-		return err
-	}
-
-	defer db.Close()
-
-	var cnt int64
-	err = db.QueryRow("SELECT COUNT(*) AS cnt FROM pragma_table_info('delegations') WHERE name='source'").Scan(&cnt)
-	if err != nil {
-		return err
-	}
-
-	if cnt == 1 {
-		return nil
-	}
-
-	sqlStmt := "alter table delegations add column source text not null default 'cube'"
-	_, err = db.Exec(sqlStmt)
-	if err != nil {
-		return err
-	}
-
-	log.Info("Successfully altered database #1!")
-
-	return nil
-}
-
-func alterDatabaseIfNeeded2(rootDir string) error {
-	stakeDbPath := filepath.Join(rootDir, "data", travisUtils.DB_FILE_NAME)
-	db, err := sql.Open("sqlite3", stakeDbPath)
-	if err != nil {
-		return err
-	}
-
-	defer db.Close()
-
-	// add the completely_withdraw field to delegations table
-	var cnt int64
-	err = db.QueryRow("SELECT COUNT(*) AS cnt FROM pragma_table_info('delegations') WHERE name='completely_withdraw'").Scan(&cnt)
-	if err != nil {
-		return err
-	}
-
-	if cnt == 1 {
-		return nil
-	}
-
-	sqlStmt := "alter table delegations add column completely_withdraw text not null default 'N'"
-	_, err = db.Exec(sqlStmt)
-	if err != nil {
-		return err
-	}
-
-	// add the actual_amount field to unstake_requests table
-	err = db.QueryRow("SELECT COUNT(*) AS cnt FROM pragma_table_info('unstake_requests') WHERE name='actual_amount'").Scan(&cnt)
-	if err != nil {
-		return err
-	}
-
-	if cnt == 1 {
-		return nil
-	}
-
-	sqlStmt = "alter table unstake_requests add column actual_amount text not null default '0'"
-	_, err = db.Exec(sqlStmt)
-	if err != nil {
-		return err
-	}
-
-	log.Info("Successfully altered database #2!")
-
-	return nil
-}
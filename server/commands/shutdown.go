@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	tcmd "github.com/tendermint/tendermint/cmd/tendermint/commands"
+	tmdb "github.com/tendermint/tendermint/libs/db"
+	sm "github.com/tendermint/tendermint/state"
+
+	"github.com/CyberMiles/travis/app"
+	"github.com/CyberMiles/travis/sdk/dbm"
+	"github.com/CyberMiles/travis/server"
+)
+
+// shutdownTimeout bounds each step of the shutdown
+const shutdownTimeout = 10 * time.Second
+
+// shutdownManager stops the node once, whether it's asked by a signal or by a
+// retiring proposal: the rpc stops accepting transactions, the block in flight
+// is committed, then tendermint, the vm, which flushes its state, and the
+// sqlite db are stopped in turn
+type shutdownManager struct {
+	mtx  sync.Mutex
+	once sync.Once
+	srvs *Services
+}
+
+func (m *shutdownManager) setServices(srvs *Services) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.srvs = srvs
+}
+
+func (m *shutdownManager) shutdown(reason string) {
+	m.once.Do(func() {
+		logger.Info("Shutting down", "reason", reason)
+		server.SetShuttingDown()
+
+		m.mtx.Lock()
+		srvs := m.srvs
+		m.mtx.Unlock()
+		if srvs != nil {
+			if !srvs.app.Halt(shutdownTimeout) {
+				logger.Error("The block in flight was not committed in time, it is rolled back and replayed at the restart")
+			}
+			stopWithTimeout("tendermint", srvs.tmNode.Stop)
+			stopWithTimeout("vm", srvs.emNode.Stop)
+		}
+		dbm.Sqliter.CloseDB()
+		logger.Info("Shut down")
+	})
+}
+
+func stopWithTimeout(name string, stop func() error) {
+	done := make(chan error, 1)
+	go func() {
+		done <- stop()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			logger.Error("Error in stopping", "service", name, "err", err)
+		}
+	case <-time.After(shutdownTimeout):
+		logger.Error("Timeout in stopping", "service", name)
+	}
+}
+
+// listen shuts down the node when the retiring proposal asks to, and exits
+func (m *shutdownManager) listen() {
+	<-server.StopFlag
+	m.shutdown("retired")
+	os.Exit(0)
+}
+
+// checkStores refuses to start if a crash left the stores of the app at
+// different heights or with an app hash tendermint did not record
+func checkStores(baseApp *app.BaseApp) error {
+	cfg, err := tcmd.ParseConfig()
+	if err != nil {
+		return err
+	}
+	stateDB := tmdb.NewDB("state", tmdb.DBBackendType(cfg.DBBackend), cfg.DBDir())
+	state := sm.LoadState(stateDB)
+	stateDB.Close()
+
+	db, err := dbm.Sqliter.GetDB()
+	if err != nil {
+		return err
+	}
+	return baseApp.CheckStores(db, state.LastBlockHeight, state.AppHash)
+}
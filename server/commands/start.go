@@ -9,6 +9,7 @@ import (
 	"net/rpc"
 	"os"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -16,21 +17,26 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/ethereum/go-ethereum/eth"
-	cstypes "github.com/tendermint/tendermint/consensus/types"
 	"github.com/tendermint/tendermint/libs/cli"
 	cmn "github.com/tendermint/tendermint/libs/common"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
 
 	"github.com/CyberMiles/travis/app"
+	"github.com/CyberMiles/travis/metrics"
 	"github.com/CyberMiles/travis/modules/stake"
 	"github.com/CyberMiles/travis/sdk/dbm"
-	"github.com/CyberMiles/travis/server"
 	"github.com/CyberMiles/travis/types"
 	"github.com/CyberMiles/travis/utils"
 	"github.com/CyberMiles/travis/version"
 )
 
 const (
-	SubFlag = "sub"
+	SubFlag             = "sub"
+	SuperviseFlag       = "supervise"
+	RollbackBlocksFlag  = "rollback-blocks"
+	RollbackTimeoutFlag = "rollback-timeout"
+	LogMaxSizeFlag      = "log-max-size"
+	LogMaxBackupsFlag   = "log-max-backups"
 )
 
 // GetStartCmd - initialize a command as the start command with tick
@@ -41,6 +47,11 @@ func GetStartCmd() *cobra.Command {
 		RunE:  startCmd(),
 	}
 	startCmd.PersistentFlags().Bool(SubFlag, false, "start travis as sub process")
+	startCmd.PersistentFlags().Bool(SuperviseFlag, false, "run travis as a sub process which is restarted on crash and upgraded by governance")
+	startCmd.PersistentFlags().Int64(RollbackBlocksFlag, 5, "blocks the new version must commit after an upgrade, otherwise the previous version is restored (supervise mode)")
+	startCmd.PersistentFlags().Duration(RollbackTimeoutFlag, 10*time.Minute, "time the new version has to commit the rollback blocks (supervise mode)")
+	startCmd.PersistentFlags().Int64(LogMaxSizeFlag, 100, "size in MB the log file of the sub process is rotated at (supervise mode)")
+	startCmd.PersistentFlags().Int(LogMaxBackupsFlag, 10, "number of rotated log files kept (supervise mode)")
 	return startCmd
 }
 
@@ -52,14 +63,16 @@ func startCmd() func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		rootDir := viper.GetString(cli.HomeFlag)
 		// start travis as sub process
-		/*
-			if !viper.GetBool(SubFlag) {
-				return startSubProcess(rootDir)
-			}
-		*/
+		if viper.GetBool(SuperviseFlag) && !viper.GetBool(SubFlag) {
+			return startSubProcess(rootDir)
+		}
 		if err := dbm.InitSqliter(path.Join(rootDir, "data", utils.DB_FILE_NAME)); err != nil {
 			return err
 		}
+		// refuses a database of a newer program, otherwise applies the pending migrations
+		if err := migrateDatabase(rootDir); err != nil {
+			return err
+		}
 
 		cmdName := cmd.Root().Name()
 		appName := fmt.Sprintf("%s v%v", cmdName, version.Version)
@@ -77,45 +90,24 @@ func startCmd() func(cmd *cobra.Command, args []string) error {
 }
 
 func start(rootDir string, storeApp *app.StoreApp) error {
-	var srvs *Services
-	go func() {
-		// Listen for the stop channel
-		<-server.StopFlag
-		if srvs != nil {
-			if srvs.tmNode != nil {
-				// make sure tendermint is not in commit step
-				for srvs.tmNode.ConsensusState().Step == cstypes.RoundStepCommit {
-					time.Sleep(time.Second * 1)
-				}
-				srvs.tmNode.Stop()
-			}
-			if srvs.emNode != nil {
-				srvs.emNode.Stop()
-			}
+	m := &shutdownManager{}
+	go m.listen()
+
+	if config.Metrics.Enabled {
+		if err := metrics.Start(config.Metrics.ListenAddr); err != nil {
+			return errors.Wrap(err, "start metrics")
 		}
-		dbm.Sqliter.CloseDB()
-		os.Exit(0)
-	}()
+	}
 
 	srvs, err := startServices(rootDir, storeApp)
 	if err != nil {
 		return errors.Errorf("Error in start services: %v\n", err)
 	}
+	m.setServices(srvs)
 
 	// wait forever
 	cmn.TrapSignal(func() {
-		// make sure tendermint is not in commit step
-		var times uint
-		for srvs.tmNode.ConsensusState().Step == cstypes.RoundStepCommit {
-			time.Sleep(time.Second * 1)
-			times++
-			if times >= 5 {
-				break
-			}
-		}
-		srvs.tmNode.Stop()
-		srvs.emNode.Stop()
-		dbm.Sqliter.CloseDB()
+		m.shutdown("signal")
 	})
 
 	return nil
@@ -139,14 +131,30 @@ func createBaseApp(rootDir string, storeApp *app.StoreApp, ethApp *app.Ethermint
 
 			app.SetChainId(genDoc.ChainID)
 			utils.SetParams(genDoc.Params)
-			for _, val := range genDoc.Validators {
-				stake.SetGenesisValidator(val, app.Append())
+			if genDoc.IsExported() {
+				// the staked coins are in the exported vm state already
+				if err := importGenesis(genDoc, app.Append()); err != nil {
+					return nil, errors.Errorf("Error in importing the exported genesis: %v\n", err)
+				}
+			} else {
+				for _, val := range genDoc.Validators {
+					stake.SetGenesisValidator(val, app.Append())
+				}
+				for _, d := range genDoc.Delegations {
+					if err := stake.SetGenesisDelegation(d, app.Append()); err != nil {
+						return nil, errors.Errorf("Error in the genesis delegation of %s: %v\n", d.DelegatorAddress, err)
+					}
+				}
 			}
 		} else {
 			fmt.Printf("No genesis file at %s, skipping...\n", genesisFile)
 		}
 	}
 
+	if err := checkStores(app); err != nil {
+		return nil, err
+	}
+
 	chainID := app.GetChainID()
 	logger.Info("Starting Travis", "chain_id", chainID)
 
@@ -174,28 +182,78 @@ func startSubProcess(rootDir string) error {
 	args := os.Args[1:]
 	args = append(args, arg)
 
-	cmd := types.NewTravisCmd(rootDir, path.Base(os.Args[0]), args...)
+	name := path.Base(os.Args[0])
+	if err := installProgram(rootDir, name); err != nil {
+		return err
+	}
+
+	output, err := types.NewRotatingFile(path.Join(rootDir, "logs", name+".log"),
+		viper.GetInt64(LogMaxSizeFlag)*1024*1024, viper.GetInt(LogMaxBackupsFlag))
+	if err != nil {
+		return err
+	}
+
+	cmd := types.NewTravisCmd(rootDir, name, args...)
+	cmd.Output = output
+	cmd.RollbackBlocks = viper.GetInt64(RollbackBlocksFlag)
+	cmd.RollbackTimeout = viper.GetDuration(RollbackTimeoutFlag)
+	cmd.Height = latestHeight(config.TMConfig.RPC.ListenAddress)
+
 	m := types.NewMonitor(cmd)
-	startRPC(m)
-	cmd.Start()
+	if err := startRPC(m); err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	logger.Info("Supervising travis", "program", path.Join(cmd.Path, name), "log", path.Join(rootDir, "logs", name+".log"))
 
 	go startRoutine(cmd)
 
 	cmn.TrapSignal(func() {
 		cmd.Stop()
-		time.Sleep(time.Second * 1)
+		output.Close()
 	})
 
 	return nil
 }
 
+// installProgram links the running program into the bin directory the sub process is started from
+func installProgram(rootDir, name string) error {
+	program := path.Join(rootDir, "bin", name)
+	if _, err := os.Stat(program); err == nil {
+		return nil
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Join(rootDir, "bin"), 0755); err != nil {
+		return err
+	}
+	return os.Symlink(exe, program)
+}
+
+// latestHeight returns the latest block height of the sub process from its tendermint rpc
+func latestHeight(laddr string) func() (int64, error) {
+	addr := strings.Replace(laddr, "0.0.0.0", "127.0.0.1", 1)
+	client := rpcclient.NewHTTP(addr, "/websocket")
+	return func() (int64, error) {
+		status, err := client.Status()
+		if err != nil {
+			return 0, err
+		}
+		return status.SyncInfo.LatestBlockHeight, nil
+	}
+}
+
 func startRPC(m *types.Monitor) error {
 	rpc.Register(m)
 	rpc.HandleHTTP()
 
 	l, e := net.Listen("tcp", "127.0.0.1:"+utils.MonitorRpcPort)
 	if e != nil {
-		log.Fatal("listen error:", e)
+		return errors.Wrap(e, "listen monitor rpc")
 	}
 	go http.Serve(l, nil)
 	return nil
@@ -206,20 +264,23 @@ func startRoutine(c *types.TravisCmd) {
 		select {
 		case cmdInfo := <-c.DownloadChan:
 			fmt.Printf("Start to download %s\n", cmdInfo.Name)
+			// the failure is reported through the Monitor.Status
 			if err := c.Download(cmdInfo); err != nil {
-				log.Fatalf("Download failed: %s\n", err)
+				log.Printf("Download failed: %s\n", err)
 			}
 		case cmdInfo := <-c.UpgradeChan:
 			fmt.Printf("Start to upgrade %s\n", cmdInfo.Name)
 			if c.NextName != cmdInfo.ReleaseName() {
-				log.Fatalf("Upgrade want version (%s) but get version: (%s)\n", cmdInfo.Name, c.NextName)
+				log.Printf("Upgrade want version (%s) but get version: (%s)\n", cmdInfo.ReleaseName(), c.NextName)
+				continue
 			}
+			// the sub process keeps running the current version if the upgrade fails
 			if err := c.Upgrade(cmdInfo); err != nil {
-				log.Fatalf("Upgrade failed: %s\n", err)
+				log.Printf("Upgrade failed: %s\n", err)
 			}
 		case <-c.KillChan:
 			if err := c.Kill(); err != nil {
-				log.Fatalf("Kill process failed: %s\n", err)
+				log.Printf("Kill process failed: %s\n", err)
 			}
 		}
 	}
@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/p2p"
+	pv "github.com/tendermint/tendermint/privval"
+
+	gen "github.com/CyberMiles/travis/misc/genesis"
+	"github.com/CyberMiles/travis/sdk"
+	"github.com/CyberMiles/travis/types"
+	"github.com/CyberMiles/travis/utils"
+)
+
+const (
+	FlagValidators  = "validators"
+	FlagKeyPassword = "key-password"
+	FlagBalance     = "balance"
+	FlagBasePort    = "base-port"
+)
+
+// TestnetCmd creates the homes of the nodes of a local testnet
+var TestnetCmd = GetTestnetCmd()
+
+func GetTestnetCmd() *cobra.Command {
+	testnetCmd := &cobra.Command{
+		Use:   "testnet",
+		Short: "Initialize the nodes of a local testnet",
+		Long: `Initialize the homes of the nodes of a testnet running on localhost.
+
+Every node is a genesis validator with its own private validator, node key and
+account, the account is funded by the vm genesis and its key is in the keystore
+of the node. The nodes are the persistent peers of each other, node i listens on
+the ports of node 0 plus 10*i. The metrics and health endpoints of node 0 are on
+the P2P port plus 5 and 6. Start node i with
+  travis node start --home <output>/node<i>`,
+		RunE: testnetFiles,
+	}
+	testnetCmd.Flags().Int(FlagValidators, 4, "Number of validators")
+	testnetCmd.Flags().String(FlagOutput, "./mytestnet", "Directory the node homes are created in")
+	testnetCmd.Flags().String(FlagChainID, "local", "Chain ID")
+	testnetCmd.Flags().String(FlagKeyPassword, "1234", "Password of the validator accounts")
+	testnetCmd.Flags().Int64(FlagBalance, 100000000, "CMTs allocated to each validator account")
+	testnetCmd.Flags().Int(FlagBasePort, 26656, "P2P port of node 0, its RPC port is the next one")
+	return testnetCmd
+}
+
+// testnetNode holds what the nodes of the testnet need to know about each other
+type testnetNode struct {
+	home    string
+	id      p2p.ID
+	pubKey  types.PubKey
+	address common.Address
+}
+
+func testnetFiles(cmd *cobra.Command, args []string) error {
+	n := viper.GetInt(FlagValidators)
+	if n < 1 {
+		return errors.New("At least one validator is needed")
+	}
+	output, err := filepath.Abs(viper.GetString(FlagOutput))
+	if err != nil {
+		return err
+	}
+	basePort := viper.GetInt(FlagBasePort)
+
+	// the keys of the nodes
+	nodes := make([]testnetNode, n)
+	for i := range nodes {
+		home := filepath.Join(output, fmt.Sprintf("node%d", i))
+		if cmn.FileExists(filepath.Join(home, defaultConfigDir, configFile)) {
+			return errors.Errorf("%s is initialized already", home)
+		}
+		conf := DefaultConfig()
+		conf.TMConfig.SetRoot(home)
+		if err = cmn.EnsureDir(filepath.Join(home, defaultConfigDir), 0700); err != nil {
+			return err
+		}
+
+		privValidator := pv.GenFilePV(conf.TMConfig.PrivValidatorFile())
+		privValidator.Save()
+		nodeKey, err := p2p.LoadOrGenNodeKey(conf.TMConfig.NodeKeyFile())
+		if err != nil {
+			return err
+		}
+		address, err := keystore.StoreKey(filepath.Join(home, "keystore"), viper.GetString(FlagKeyPassword), keystore.LightScryptN, keystore.LightScryptP)
+		if err != nil {
+			return err
+		}
+		nodes[i] = testnetNode{home, nodeKey.ID(), types.PubKey{privValidator.GetPubKey()}, address}
+	}
+
+	// the genesis shared by the nodes
+	genDoc := &types.GenesisDoc{
+		GenesisTime: time.Now(),
+		ChainID:     viper.GetString(FlagChainID),
+		Params:      utils.DefaultParams(),
+	}
+	if n > int(genDoc.Params.MaxVals) {
+		genDoc.Params.MaxVals = uint16(n)
+	}
+	vmGenesis := gen.DevGenesisBlock()
+	vmGenesis.Config.ChainID = new(big.Int).SetUint64(uint64(utils.PrivateChain))
+	balance := utils.ToWei(viper.GetInt64(FlagBalance))
+	for i, node := range nodes {
+		genDoc.Validators = append(genDoc.Validators, types.GenesisValidator{
+			PubKey:    node.pubKey,
+			Power:     "10",
+			Shares:    1000000,
+			Name:      fmt.Sprintf("node%d", i),
+			Address:   node.address.String(),
+			CompRate:  sdk.NewRat(2, 10),
+			MaxAmount: 10000000,
+		})
+		vmGenesis.Alloc[node.address] = core.GenesisAccount{Balance: new(big.Int).Set(balance)}
+	}
+	if err = genDoc.Validate(); err != nil {
+		return err
+	}
+	if err = checkStakeAlloc(genDoc, vmGenesis.Alloc); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(vmGenesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(filepath.Join(output, "vm-genesis.json"), b, 0644); err != nil {
+		return err
+	}
+
+	for i, node := range nodes {
+		var peers []string
+		for j, peer := range nodes {
+			if j != i {
+				peers = append(peers, fmt.Sprintf("%s@127.0.0.1:%d", peer.id, basePort+10*j))
+			}
+		}
+
+		conf := DefaultConfig()
+		conf.TMConfig.SetRoot(node.home)
+		conf.TMConfig.Moniker = fmt.Sprintf("node%d", i)
+		conf.TMConfig.P2P.ListenAddress = fmt.Sprintf("tcp://0.0.0.0:%d", basePort+10*i)
+		conf.TMConfig.P2P.PersistentPeers = strings.Join(peers, ",")
+		conf.TMConfig.P2P.AddrBookStrict = false
+		conf.TMConfig.P2P.AllowDuplicateIP = true
+		conf.TMConfig.RPC.ListenAddress = fmt.Sprintf("tcp://0.0.0.0:%d", basePort+10*i+1)
+		conf.EMConfig.ChainId = utils.PrivateChain
+		conf.EMConfig.ABCIAddr = fmt.Sprintf("tcp://0.0.0.0:%d", 8848+10*i)
+		conf.EMConfig.RPCPortFlag += uint(10 * i)
+		conf.EMConfig.WSPortFlag += uint(10 * i)
+		conf.EMConfig.ListenPortFlag += uint(10 * i)
+		conf.Metrics.ListenAddr = fmt.Sprintf("0.0.0.0:%d", basePort+10*i+5)
+		conf.Health.ListenAddr = fmt.Sprintf("0.0.0.0:%d", basePort+10*i+6)
+		ensureRoot(conf)
+
+		if err = genDoc.SaveAs(conf.TMConfig.GenesisFile()); err != nil {
+			return err
+		}
+		if err = migrateDatabase(node.home); err != nil {
+			return err
+		}
+		if _, err = setupVmGenesis(node.home, vmGenesis); err != nil {
+			return err
+		}
+		logger.Info("Initialized node", "home", node.home, "address", node.address.String(),
+			"p2p", conf.TMConfig.P2P.ListenAddress, "rpc", conf.TMConfig.RPC.ListenAddress, "vm_rpc_port", conf.EMConfig.RPCPortFlag)
+	}
+	return nil
+}
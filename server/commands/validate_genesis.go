@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+	"math/big"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/tendermint/tendermint/libs/cli"
+
+	"github.com/CyberMiles/travis/types"
+	"github.com/CyberMiles/travis/utils"
+	emtUtils "github.com/CyberMiles/travis/vm/cmd/utils"
+)
+
+// ValidateGenesisCmd checks the genesis files before the chain is started
+var ValidateGenesisCmd = GetValidateGenesisCmd()
+
+func GetValidateGenesisCmd() *cobra.Command {
+	validateCmd := &cobra.Command{
+		Use:   "validate-genesis [genesis.json]",
+		Short: "Validate the genesis file and the vm genesis",
+		Long: `Validate the genesis file, the one in the config directory by default.
+
+The params, the addresses, the self-staking of the genesis validators and the
+initial delegations are checked, and the vm genesis must allocate the staked coins
+to the validators and the delegators.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: validateGenesisCmd,
+	}
+	validateCmd.Flags().String(FlagVMGenesis, "", "VM genesis file, the default one of init if empty")
+	return validateCmd
+}
+
+func validateGenesisCmd(cmd *cobra.Command, args []string) error {
+	genesisFile := path.Join(viper.GetString(cli.HomeFlag), DefaultConfig().TMConfig.GenesisFile())
+	if len(args) > 0 {
+		genesisFile = args[0]
+	}
+	genDoc, err := loadGenesis(genesisFile)
+	if err != nil {
+		return err
+	}
+	if err = genDoc.Validate(); err != nil {
+		return errors.Wrap(err, genesisFile)
+	}
+
+	vmGenesis, err := emtUtils.ParseGenesisOrDefault(viper.GetString(FlagVMGenesis), config.EMConfig.ChainId)
+	if err != nil {
+		return errors.Wrap(err, "loading vm genesis")
+	}
+	if err = checkStakeAlloc(genDoc, vmGenesis.Alloc); err != nil {
+		return err
+	}
+
+	fmt.Printf("The genesis of %s is valid, %d validators, %d delegations\n", genDoc.ChainID, len(genDoc.Validators), len(genDoc.Delegations))
+	return nil
+}
+
+// checkStakeAlloc checks the staked coins are allocated to the accounts, the
+// genesis validators and delegations would fail to start the chain otherwise
+func checkStakeAlloc(genDoc *types.GenesisDoc, alloc core.GenesisAlloc) error {
+	for addr, stake := range genDoc.Stakes() {
+		balance := big.NewInt(0)
+		if account, ok := alloc[addr]; ok && account.Balance != nil {
+			balance = account.Balance
+		}
+		if balance.Cmp(stake) < 0 {
+			return errors.Errorf("%s stakes %v CMTs at the genesis but has only %v allocated", addr.Hex(), toCmt(stake), toCmt(balance))
+		}
+	}
+	return nil
+}
+
+func toCmt(wei *big.Int) string {
+	return new(big.Rat).SetFrac(wei, utils.ToWei(1)).FloatString(2)
+}
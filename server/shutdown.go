@@ -0,0 +1,15 @@
+package server
+
+import "sync/atomic"
+
+var shuttingDown int32
+
+// SetShuttingDown tells the node is shutting down, the rpc accepts no more transactions
+func SetShuttingDown() {
+	atomic.StoreInt32(&shuttingDown, 1)
+}
+
+// ShuttingDown tells whether the node is shutting down
+func ShuttingDown() bool {
+	return atomic.LoadInt32(&shuttingDown) == 1
+}
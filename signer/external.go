@@ -0,0 +1,128 @@
+// Package signer sends the txs to be signed to an external signer, so the
+// keys of the validator owner accounts can live in an HSM or a hardware wallet
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// requestTimeout bounds a signing request, the signer may wait for the
+// approval of an operator
+const requestTimeout = 5 * time.Minute
+
+// External is an external signer speaking the account api of clef over
+// http(s), websocket or a local socket
+type External struct {
+	endpoint string
+	client   *rpc.Client
+}
+
+// NewExternal connects to the signer, the endpoint is an url or the path of a local socket
+func NewExternal(endpoint string) (*External, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't connect to the external signer at %s: %v", endpoint, err)
+	}
+	return &External{endpoint, client}, nil
+}
+
+func (e *External) Endpoint() string {
+	return e.endpoint
+}
+
+// Accounts lists the accounts the signer holds the keys of
+func (e *External) Accounts() ([]common.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	var accounts []common.Address
+	if err := e.client.CallContext(ctx, &accounts, "account_list"); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// Has tells whether the signer holds the key of the account
+func (e *External) Has(address common.Address) bool {
+	accounts, err := e.Accounts()
+	if err != nil {
+		return false
+	}
+	for _, a := range accounts {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+// SendTxArgs is the tx to be signed, as clef takes it
+type SendTxArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice hexutil.Big     `json:"gasPrice"`
+	Value    hexutil.Big     `json:"value"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	Data     hexutil.Bytes   `json:"data"`
+	ChainId  *hexutil.Big    `json:"chainId"`
+}
+
+// SignTxResult is the tx signed by the signer
+type SignTxResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+// SignTx asks the signer to sign the tx, the signed tx is checked to be the
+// one requested and to be signed by the account
+func (e *External) SignTx(from common.Address, tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	args := SendTxArgs{
+		From:     from,
+		To:       tx.To(),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: hexutil.Big(*tx.GasPrice()),
+		Value:    hexutil.Big(*tx.Value()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		Data:     tx.Data(),
+		ChainId:  (*hexutil.Big)(chainId),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	var res SignTxResult
+	if err := e.client.CallContext(ctx, &res, "account_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("the external signer refused to sign: %v", err)
+	}
+
+	signed := new(types.Transaction)
+	if err := rlp.DecodeBytes(res.Raw, signed); err != nil {
+		return nil, fmt.Errorf("the external signer returned an invalid tx: %v", err)
+	}
+	signer := types.NewEIP155Signer(chainId)
+	if signer.Hash(signed) != signer.Hash(tx) {
+		return nil, fmt.Errorf("the external signer signed another tx")
+	}
+	sender, err := types.Sender(signer, signed)
+	if err != nil {
+		return nil, fmt.Errorf("the external signer returned an invalid signature: %v", err)
+	}
+	if sender != from {
+		return nil, fmt.Errorf("the external signer signed with %s instead of %s", sender.Hex(), from.Hex())
+	}
+	return signed, nil
+}
+
+// Close disconnects from the signer
+func (e *External) Close() {
+	e.client.Close()
+}
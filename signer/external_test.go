@@ -0,0 +1,91 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSigner signs with an in-memory key, it signs another tx if tamper is set
+type mockSigner struct {
+	key    *ecdsa.PrivateKey
+	tamper bool
+}
+
+func (s *mockSigner) List() []common.Address {
+	return []common.Address{crypto.PubkeyToAddress(s.key.PublicKey)}
+}
+
+func (s *mockSigner) SignTransaction(args SendTxArgs) (*SignTxResult, error) {
+	nonce := uint64(args.Nonce)
+	if s.tamper {
+		nonce++
+	}
+	tx := types.NewContractCreation(nonce, args.Value.ToInt(), uint64(args.Gas), args.GasPrice.ToInt(), args.Data)
+	signed, err := types.SignTx(tx, types.NewEIP155Signer(args.ChainId.ToInt()), s.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTxResult{Raw: hexutil.Bytes(raw)}, nil
+}
+
+func startMockSigner(t *testing.T, mock *mockSigner) (*External, func()) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", mock); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	ext, err := NewExternal(httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ext, func() {
+		ext.Close()
+		httpServer.Close()
+		server.Stop()
+	}
+}
+
+func TestExternalSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	mock := &mockSigner{key: key}
+	ext, stop := startMockSigner(t, mock)
+	defer stop()
+
+	assert.True(ext.Has(from))
+	assert.False(ext.Has(common.HexToAddress("0x01")))
+
+	chainId := big.NewInt(19)
+	tx := types.NewContractCreation(3, big.NewInt(0), 21000, big.NewInt(2e9), []byte(`{"type":"stake/delegate"}`))
+	signed, err := ext.SignTx(from, tx, chainId)
+	assert.Nil(err)
+	sender, err := types.Sender(types.NewEIP155Signer(chainId), signed)
+	assert.Nil(err)
+	assert.Equal(from, sender)
+	assert.Equal(tx.Nonce(), signed.Nonce())
+
+	// the key of another account
+	_, err = ext.SignTx(common.HexToAddress("0x01"), tx, chainId)
+	assert.NotNil(err)
+
+	// another tx
+	mock.tamper = true
+	_, err = ext.SignTx(from, tx, chainId)
+	assert.NotNil(err)
+}
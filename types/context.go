@@ -2,10 +2,15 @@ package types
 
 import (
 	"bytes"
-	"github.com/CyberMiles/travis/utils"
+	"math"
+	"math/big"
 	"math/rand"
 	"sort"
 
+	"github.com/CyberMiles/travis/sdk"
+	"github.com/CyberMiles/travis/sdk/errors"
+	"github.com/CyberMiles/travis/utils"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
 )
@@ -20,6 +25,8 @@ type Context struct {
 	ethappState *state.StateDB
 	nonce       uint64
 	time        int64
+	gasLimit    uint64
+	gasPrice    uint64
 }
 
 func NewContext(chain string, height, time int64, ethappState *state.StateDB) Context {
@@ -78,6 +85,12 @@ func (c Context) GetSigners() []common.Address {
 	return c.signers
 }
 
+// WithSender returns a copy of the context where the sender is the only signer
+func (c Context) WithSender(sender common.Address) Context {
+	c.signers = []common.Address{sender}
+	return c
+}
+
 // Reset should clear out all permissions,
 // but carry on knowledge that this is a child
 func (c Context) Reset() Context {
@@ -96,6 +109,53 @@ func (c Context) GetNonce() uint64 {
 	return c.nonce
 }
 
+// SetGas records the gas limit and gas price declared by the ethereum tx,
+// a price above math.MaxInt64 is rejected
+func (c *Context) SetGas(gasLimit uint64, gasPrice *big.Int) error {
+	if gasPrice != nil && (gasPrice.Sign() < 0 || gasPrice.Cmp(big.NewInt(math.MaxInt64)) > 0) {
+		return errors.ErrHighGasPrice()
+	}
+	c.gasLimit = gasLimit
+	if gasPrice != nil {
+		c.gasPrice = gasPrice.Uint64()
+	} else {
+		c.gasPrice = 0
+	}
+	return nil
+}
+
+// IsLegacyGas returns true if the tx declares neither gas limit nor gas price,
+// such txs are charged with the default gas price
+func (c Context) IsLegacyGas() bool {
+	return c.gasLimit == 0 && c.gasPrice == 0
+}
+
+func (c Context) GasLimit() uint64 {
+	return c.gasLimit
+}
+
+// GasPrice returns the gas price the tx will be charged with
+func (c Context) GasPrice() uint64 {
+	if c.IsLegacyGas() {
+		return utils.GetParams().GasPrice
+	}
+	return c.gasPrice
+}
+
+// GasFee makes sure the declared gas limit and gas price are sufficient for the
+// gas required by the tx, and returns the fee to be charged
+func (c Context) GasFee(gas uint64) (sdk.Int, error) {
+	if !c.IsLegacyGas() {
+		if c.gasLimit < gas {
+			return sdk.ZeroInt, errors.ErrLowGasLimit(gas)
+		}
+		if c.gasPrice < utils.GetParams().GasPrice {
+			return sdk.ZeroInt, errors.ErrLowGasPrice()
+		}
+	}
+	return utils.CalGasFee(gas, c.GasPrice()), nil
+}
+
 //////////////////////////////// Sort Interface
 // USAGE sort.Sort(ByAll(<common.Address>))
 
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CyberMiles/travis/sdk/errors"
+	"github.com/CyberMiles/travis/utils"
+)
+
+func TestContextGasFee(t *testing.T) {
+	assert := assert.New(t)
+	utils.SetParams(utils.DefaultParams())
+
+	ctx := NewContext("test", 1, 0, nil)
+	assert.Nil(ctx.SetGas(1000000, big.NewInt(math.MaxInt64)))
+	fee, err := ctx.GasFee(1000000)
+	assert.Nil(err)
+	expected := new(big.Int).Mul(big.NewInt(1000000), big.NewInt(math.MaxInt64))
+	assert.Equal(expected.String(), fee.String())
+
+	// a price of 2^63 would make the fee negative in int64
+	price := new(big.Int).Lsh(big.NewInt(1), 63)
+	err = ctx.SetGas(1000000, price)
+	assert.True(errors.IsHighGasPriceErr(err))
+	err = ctx.SetGas(1000000, new(big.Int).SetUint64(math.MaxUint64))
+	assert.True(errors.IsHighGasPriceErr(err))
+}
@@ -0,0 +1,183 @@
+package types
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// States of the download of a new program version
+const (
+	DownloadInProgress = "downloading"
+	DownloadReady      = "ready"
+	DownloadFailed     = "failed"
+)
+
+// DownloadStatus reports the download of a new program version to the upgrade proposal
+type DownloadStatus struct {
+	Release   string    `json:"release"`
+	State     string    `json:"state"`
+	URL       string    `json:"url"`
+	Error     string    `json:"error"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsReady tells whether the release has been downloaded and verified
+func (s DownloadStatus) IsReady(release string) bool {
+	return s.Release == release && s.State == DownloadReady
+}
+
+var downloadClient = &http.Client{Timeout: 30 * time.Minute}
+
+// fetchRelease tries the download urls in order and places the verified program at dest.
+// It returns the url the program was fetched from.
+func fetchRelease(info *CmdInfo, dest string) (string, error) {
+	if info.MD5 == "" && info.SHA256 == "" {
+		return "", errors.New("no checksum provided for " + info.ReleaseName())
+	}
+	if len(info.DownloadURLs) == 0 {
+		return "", errors.New("no download url provided for " + info.ReleaseName())
+	}
+
+	var errs []string
+	for _, url := range info.DownloadURLs {
+		err := fetchFrom(url, info, dest)
+		if err == nil {
+			return url, nil
+		}
+		log.Printf("download from %s failed: %v\n", url, err)
+		errs = append(errs, fmt.Sprintf("%s: %v", url, err))
+	}
+	return "", errors.New(strings.Join(errs, "; "))
+}
+
+func fetchFrom(url string, info *CmdInfo, dest string) error {
+	dir := filepath.Dir(dest)
+	archive, err := ioutil.TempFile(dir, "."+info.ReleaseName()+".download")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	resp, err := downloadClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	md5h, sha256h := md5.New(), sha256.New()
+	if _, err = io.Copy(io.MultiWriter(archive, md5h, sha256h), resp.Body); err != nil {
+		return err
+	}
+	if err = verifyChecksum(md5h, info.MD5, "md5"); err != nil {
+		return err
+	}
+	if err = verifyChecksum(sha256h, info.SHA256, "sha256"); err != nil {
+		return err
+	}
+
+	bin, err := ioutil.TempFile(dir, "."+info.ReleaseName()+".bin")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(bin.Name())
+	defer bin.Close()
+
+	if _, err = archive.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err = extractProgram(url, archive, info.Name, bin); err != nil {
+		return err
+	}
+	if err = bin.Sync(); err != nil {
+		return err
+	}
+	if err = bin.Chmod(0755); err != nil {
+		return err
+	}
+
+	// rename is atomic, the program is either complete or absent
+	return os.Rename(bin.Name(), dest)
+}
+
+func verifyChecksum(h hash.Hash, expected, kind string) error {
+	if expected == "" {
+		return nil
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%s mismatch, want %s, got %s", kind, expected, actual)
+	}
+	return nil
+}
+
+// extractProgram copies the program named name to w, the url tells whether the file is
+// a zip or a gzipped tar archive, otherwise the file is the program itself
+func extractProgram(url string, f *os.File, name string, w io.Writer) error {
+	switch {
+	case strings.HasSuffix(url, ".zip"):
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		zr, err := zip.NewReader(f, fi.Size())
+		if err != nil {
+			return err
+		}
+		for _, zf := range zr.File {
+			if zf.FileInfo().IsDir() || path.Base(zf.Name) != name {
+				continue
+			}
+			rc, err := zf.Open()
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			_, err = io.Copy(w, rc)
+			return err
+		}
+	case strings.HasSuffix(url, ".tar.gz") || strings.HasSuffix(url, ".tgz"):
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if hdr.Typeflag != tar.TypeReg || path.Base(hdr.Name) != name {
+				continue
+			}
+			_, err = io.Copy(w, tr)
+			return err
+		}
+	default:
+		_, err := io.Copy(w, f)
+		return err
+	}
+	return fmt.Errorf("%s not found in the archive", name)
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tarGz(name string, content []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "release/" + name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg})
+	tw.Write(content)
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestTravisCmdDownload(t *testing.T) {
+	assert := assert.New(t)
+
+	program := []byte("#!/bin/sh\necho travis\n")
+	archive := tarGz("travis", program)
+	md5sum := md5.Sum(archive)
+	sha256sum := sha256.Sum256(archive)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/corrupted.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive[:len(archive)/2])
+	})
+	mux.HandleFunc("/travis.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	})
+	mux.HandleFunc("/travis", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(program)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	root, err := ioutil.TempDir("", "travis-download")
+	assert.Nil(err)
+	defer os.RemoveAll(root)
+
+	cmd := NewTravisCmd(root, "travis")
+
+	// no mirror serves a valid file
+	info := NewCmdInfo("travis", "v0.2", []string{server.URL + "/missing", server.URL + "/corrupted.tar.gz"}, hex.EncodeToString(md5sum[:]))
+	assert.NotNil(cmd.Download(info))
+	assert.Equal(DownloadFailed, cmd.DownloadStatus().State)
+	_, err = os.Stat(filepath.Join(root, "bin", "travis_v0.2"))
+	assert.True(os.IsNotExist(err))
+
+	// fallback to the next mirror, verified with MD5
+	info.DownloadURLs = append(info.DownloadURLs, server.URL+"/travis.tar.gz")
+	assert.Nil(cmd.Download(info))
+	status := cmd.DownloadStatus()
+	assert.True(status.IsReady("travis_v0.2"))
+	assert.Equal(server.URL+"/travis.tar.gz", status.URL)
+	b, err := ioutil.ReadFile(filepath.Join(root, "bin", "travis_v0.2"))
+	assert.Nil(err)
+	assert.Equal(program, b)
+
+	// verified with SHA-256
+	info = NewCmdInfo("travis", "v0.3", []string{server.URL + "/travis.tar.gz"}, hex.EncodeToString(sha256sum[:]))
+	assert.Equal("", info.MD5)
+	assert.Nil(cmd.Download(info))
+	assert.True(cmd.DownloadStatus().IsReady("travis_v0.3"))
+
+	// checksum mismatch of a plain program
+	info = NewCmdInfo("travis", "v0.4", []string{server.URL + "/travis"}, "sha256:"+hex.EncodeToString(sha256sum[:]))
+	assert.NotNil(cmd.Download(info))
+	assert.False(cmd.DownloadStatus().IsReady("travis_v0.4"))
+
+	files, _ := ioutil.ReadDir(filepath.Join(root, "bin"))
+	assert.Equal(2, len(files), "no temporary file is left")
+}
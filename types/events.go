@@ -0,0 +1,142 @@
+package types
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Event types published by the stake and governance modules
+const (
+	EventCandidateStateChanged = "candidateStateChanged"
+	EventSlash                 = "slash"
+	EventAward                 = "award"
+	EventUnstakeCompleted      = "unstakeCompleted"
+	EventProposalDecided       = "proposalDecided"
+)
+
+// Event is a staking or governance event emitted while executing a block.
+// Addresses holds every account the event relates to, it is used for filtering.
+type Event struct {
+	Type        string                 `json:"type"`
+	BlockHeight int64                  `json:"blockHeight"`
+	Addresses   []common.Address       `json:"addresses"`
+	Data        map[string]interface{} `json:"data"`
+}
+
+// EventFilter selects events by type and related address, empty means any
+type EventFilter struct {
+	Types     []string         `json:"types"`
+	Addresses []common.Address `json:"addresses"`
+}
+
+// Match returns true if the event passes the filter
+func (f EventFilter) Match(ev *Event) bool {
+	if len(f.Types) > 0 {
+		found := false
+		for _, t := range f.Types {
+			if strings.EqualFold(t, ev.Type) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.Addresses) > 0 {
+		for _, a := range f.Addresses {
+			for _, b := range ev.Addresses {
+				if a == b {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// EventSubscription is returned by EventBus.Subscribe
+type EventSubscription struct {
+	bus    *eventBus
+	id     int64
+	filter EventFilter
+	ch     chan *Event
+}
+
+// Chan returns the channel the matched events are delivered on
+func (s *EventSubscription) Chan() <-chan *Event {
+	return s.ch
+}
+
+// Unsubscribe stops the delivery and closes the channel
+func (s *EventSubscription) Unsubscribe() {
+	s.bus.unsubscribe(s.id)
+}
+
+type eventBus struct {
+	mtx     sync.Mutex
+	pending []*Event
+	nextId  int64
+	subs    map[int64]*EventSubscription
+}
+
+// EventBus collects the events of the block being executed and
+// dispatches them to the subscribers once the block is committed
+var EventBus = &eventBus{subs: make(map[int64]*EventSubscription)}
+
+// Publish queues an event until the current block is committed
+func (b *eventBus) Publish(eventType string, blockHeight int64, addresses []common.Address, data map[string]interface{}) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.pending = append(b.pending, &Event{eventType, blockHeight, addresses, data})
+}
+
+// Discard drops the queued events, called when the block is rolled back
+func (b *eventBus) Discard() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.pending = nil
+}
+
+// Flush dispatches the queued events to the matching subscribers.
+// Slow subscribers never block the consensus, their events are dropped.
+func (b *eventBus) Flush() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	for _, ev := range b.pending {
+		for _, s := range b.subs {
+			if !s.filter.Match(ev) {
+				continue
+			}
+			select {
+			case s.ch <- ev:
+			default:
+			}
+		}
+	}
+	b.pending = nil
+}
+
+// Subscribe registers a new subscriber with the given filter
+func (b *eventBus) Subscribe(filter EventFilter) *EventSubscription {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.nextId++
+	s := &EventSubscription{bus: b, id: b.nextId, filter: filter, ch: make(chan *Event, 256)}
+	b.subs[s.id] = s
+	return s
+}
+
+func (b *eventBus) unsubscribe(id int64) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if s, ok := b.subs[id]; ok {
+		delete(b.subs, id)
+		close(s.ch)
+	}
+}
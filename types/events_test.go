@@ -0,0 +1,59 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventBus(t *testing.T) {
+	assert := assert.New(t)
+
+	bus := &eventBus{subs: make(map[int64]*EventSubscription)}
+	alice, bob := common.HexToAddress("0xa1"), common.HexToAddress("0xb2")
+	all := bus.Subscribe(EventFilter{})
+	slashes := bus.Subscribe(EventFilter{Types: []string{"SLASH"}, Addresses: []common.Address{alice}})
+
+	// nothing is delivered before the block is committed
+	bus.Publish(EventSlash, 1, []common.Address{alice}, nil)
+	bus.Publish(EventAward, 1, []common.Address{alice}, nil)
+	bus.Publish(EventSlash, 1, []common.Address{bob}, nil)
+	assert.Len(all.Chan(), 0)
+
+	// commit
+	bus.Flush()
+	assert.Len(all.Chan(), 3)
+	assert.Len(slashes.Chan(), 1)
+	ev := <-slashes.Chan()
+	assert.Equal(EventSlash, ev.Type)
+	assert.Equal([]common.Address{alice}, ev.Addresses)
+	for i := 0; i < 3; i++ {
+		<-all.Chan()
+	}
+
+	// the events of a rolled back block are never delivered
+	bus.Publish(EventSlash, 2, []common.Address{alice}, nil)
+	bus.Discard()
+	bus.Flush()
+	assert.Len(all.Chan(), 0)
+	assert.Len(slashes.Chan(), 0)
+
+	// a full subscriber does not block the others
+	for i := 0; i < cap(all.ch)+1; i++ {
+		bus.Publish(EventCandidateStateChanged, 3, []common.Address{bob}, nil)
+	}
+	bus.Publish(EventSlash, 3, []common.Address{alice}, nil)
+	bus.Flush()
+	assert.Len(all.Chan(), cap(all.ch))
+	assert.Len(slashes.Chan(), 1)
+
+	// unsubscribe closes the channel
+	slashes.Unsubscribe()
+	<-slashes.Chan()
+	_, ok := <-slashes.Chan()
+	assert.False(ok)
+	bus.Publish(EventSlash, 4, []common.Address{alice}, nil)
+	bus.Flush()
+	assert.Len(bus.subs, 1)
+}
@@ -2,8 +2,11 @@ package types
 
 import (
 	"encoding/json"
+	"math/big"
+
 	"github.com/CyberMiles/travis/sdk"
 	"github.com/CyberMiles/travis/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/types"
@@ -23,6 +26,17 @@ type GenesisDoc struct {
 	Validators      []GenesisValidator     `json:"validators"`
 	AppHash         []byte                 `json:"app_hash"`
 	Params          *utils.Params          `json:"params"`
+
+	// The state exported from a running chain to restart it, the block heights
+	// are relative to the exported height which is the height 0 of the new chain
+	ExportedHeight   int64                   `json:"exported_height,omitempty"`
+	Candidates       []GenesisCandidate      `json:"candidates,omitempty"`
+	Delegations      []GenesisDelegation     `json:"delegations,omitempty"`
+	UnstakeRequests  []GenesisUnstakeRequest `json:"unstake_requests,omitempty"`
+	Proposals        []GenesisProposal       `json:"proposals,omitempty"`
+	Sponsorships     []GenesisSponsorship    `json:"sponsorships,omitempty"`
+	Multisigs        []GenesisMultisig       `json:"multisigs,omitempty"`
+	AbsentValidators []GenesisAbsence        `json:"absent_validators,omitempty"`
 }
 
 // GenesisValidator is an initial validator.
@@ -40,6 +54,104 @@ type GenesisValidator struct {
 	Profile   string  `json:profile`
 }
 
+// GenesisCandidate is a candidate exported from a running chain
+type GenesisCandidate struct {
+	PubKey             PubKey  `json:"pub_key"`
+	OwnerAddress       string  `json:"owner_address"`
+	Shares             string  `json:"shares"`
+	VotingPower        int64   `json:"voting_power"`
+	PendingVotingPower int64   `json:"pending_voting_power"`
+	MaxShares          string  `json:"max_shares"`
+	CompRate           sdk.Rat `json:"comp_rate"`
+	Name               string  `json:"name"`
+	Website            string  `json:"website"`
+	Location           string  `json:"location"`
+	Email              string  `json:"email"`
+	Profile            string  `json:"profile"`
+	Verified           string  `json:"verified"`
+	Active             string  `json:"active"`
+	Rank               int64   `json:"rank"`
+	State              string  `json:"state"`
+	NumOfDelegators    int64   `json:"num_of_delegators"`
+	CreatedAt          int64   `json:"created_at"`
+}
+
+// GenesisDelegation is a delegation exported from a running chain. In a genesis
+// which is not exported, it is an initial delegation to a genesis validator of
+// which only the addresses and the DelegateAmount in wei are used.
+type GenesisDelegation struct {
+	DelegatorAddress      string  `json:"delegator_address"`
+	ValidatorAddress      string  `json:"validator_address"`
+	DelegateAmount        string  `json:"delegate_amount"`
+	AwardAmount           string  `json:"award_amount"`
+	WithdrawAmount        string  `json:"withdraw_amount"`
+	PendingWithdrawAmount string  `json:"pending_withdraw_amount"`
+	SlashAmount           string  `json:"slash_amount"`
+	CompRate              sdk.Rat `json:"comp_rate"`
+	VotingPower           int64   `json:"voting_power"`
+	AverageStakingDate    int64   `json:"average_staking_date"`
+	Source                string  `json:"source"`
+	CompletelyWithdraw    string  `json:"completely_withdraw"`
+	CreatedAt             int64   `json:"created_at"`
+}
+
+// GenesisAbsence counts the consecutive blocks a validator of a running chain
+// was absent from up to the exported height
+type GenesisAbsence struct {
+	PubKey          string `json:"pub_key"`
+	Count           int16  `json:"count"`
+	LastBlockHeight int64  `json:"last_block_height"`
+}
+
+// GenesisUnstakeRequest is a pending unstake request exported from a running chain
+type GenesisUnstakeRequest struct {
+	DelegatorAddress     string `json:"delegator_address"`
+	ValidatorAddress     string `json:"validator_address"`
+	PerformedBlockHeight int64  `json:"performed_block_height"`
+	Amount               string `json:"amount"`
+}
+
+// GenesisProposal is an undecided proposal exported from a running chain
+type GenesisProposal struct {
+	Id                string                 `json:"id"`
+	Type              string                 `json:"type"`
+	Proposer          string                 `json:"proposer"`
+	ExpireTimestamp   int64                  `json:"expire_timestamp"`
+	ExpireBlockHeight int64                  `json:"expire_block_height"`
+	Detail            map[string]interface{} `json:"detail"`
+	Votes             []GenesisVote          `json:"votes"`
+}
+
+// GenesisVote is a vote on an exported proposal
+type GenesisVote struct {
+	Voter  string `json:"voter"`
+	Answer string `json:"answer"`
+}
+
+// GenesisSponsorship is a gas sponsorship exported from a running chain
+type GenesisSponsorship struct {
+	Contract       string `json:"contract"`
+	Owner          string `json:"owner"`
+	Balance        string `json:"balance"`
+	MaxFeePerUser  string `json:"max_fee_per_user"`
+	MaxFeePerBlock string `json:"max_fee_per_block"`
+	TotalSponsored string `json:"total_sponsored"`
+}
+
+// GenesisMultisig is a multisig account exported from a running chain
+type GenesisMultisig struct {
+	Address   string   `json:"address"`
+	Creator   string   `json:"creator"`
+	Members   []string `json:"members"`
+	Threshold int      `json:"threshold"`
+}
+
+// IsExported tells whether the genesis restarts an exported chain, its stake is
+// imported as is instead of being declared by the genesis validators
+func (genDoc *GenesisDoc) IsExported() bool {
+	return len(genDoc.Candidates) > 0
+}
+
 // SaveAs is a utility method for saving GenensisDoc as a JSON file.
 func (genDoc *GenesisDoc) SaveAs(file string) error {
 	genDocBytes, err := json.MarshalIndent(genDoc, "", "\t")
@@ -93,6 +205,139 @@ func (genDoc *GenesisDoc) ValidateAndComplete() error {
 	return nil
 }
 
+// Validate checks the params and the staking state of the genesis beyond
+// ValidateAndComplete, to find the mistakes before the chain is started
+func (genDoc *GenesisDoc) Validate() error {
+	if err := genDoc.ValidateAndComplete(); err != nil {
+		return err
+	}
+	if genDoc.Params == nil {
+		return errors.Errorf("The genesis file must include params")
+	}
+	if err := genDoc.Params.Validate(); err != nil {
+		return errors.Wrap(err, "Invalid params")
+	}
+
+	pubKeys := make(map[string]bool)
+	addrs := make(map[common.Address]bool)
+	// the wei a validator can still take, as its max_amount caps the delegations
+	room := make(map[common.Address]*big.Int)
+	for i, v := range genDoc.Validators {
+		if v.PubKey.PubKey == nil {
+			return errors.Errorf("Validator %d has no pub_key", i)
+		}
+		pk := PubKeyString(v.PubKey)
+		if pubKeys[pk] {
+			return errors.Errorf("Validator %d has a duplicate pub_key %s", i, pk)
+		}
+		pubKeys[pk] = true
+		if power, err := strconv.ParseInt(v.Power, 10, 64); err != nil || power <= 0 {
+			return errors.Errorf("Validator %d has an invalid power %q", i, v.Power)
+		}
+		if genDoc.IsExported() {
+			continue
+		}
+
+		addr, err := parseAddress(v.Address)
+		if err != nil {
+			return errors.Errorf("Validator %d: %v", i, err)
+		}
+		if addrs[addr] {
+			return errors.Errorf("Validator %d has a duplicate address %s", i, v.Address)
+		}
+		addrs[addr] = true
+		if v.CompRate.IsNil() || v.CompRate.LTE(sdk.ZeroRat) || v.CompRate.GTE(sdk.OneRat) {
+			return errors.Errorf("Validator %d has a comp_rate not between 0 and 1", i)
+		}
+		if v.MaxAmount <= 0 {
+			return errors.Errorf("Validator %d has a max_amount %d which is not positive", i, v.MaxAmount)
+		}
+		if v.Shares > v.MaxAmount {
+			return errors.Errorf("Validator %d stakes %d over its max_amount %d", i, v.Shares, v.MaxAmount)
+		}
+		selfStaking := sdk.NewInt(v.MaxAmount).MulRat(genDoc.Params.SelfStakingRatio)
+		if sdk.NewInt(v.Shares).LT(selfStaking) {
+			return errors.Errorf("Validator %d stakes %d below the self-staking amount %v of its max_amount %d", i, v.Shares, selfStaking, v.MaxAmount)
+		}
+		room[addr] = new(big.Int).Sub(utils.ToWei(v.MaxAmount), utils.ToWei(v.Shares))
+	}
+
+	for i, c := range genDoc.Candidates {
+		addr, err := parseAddress(c.OwnerAddress)
+		if err != nil {
+			return errors.Errorf("Candidate %d: %v", i, err)
+		}
+		if addrs[addr] {
+			return errors.Errorf("Candidate %d has a duplicate address %s", i, c.OwnerAddress)
+		}
+		addrs[addr] = true
+	}
+
+	for i, d := range genDoc.Delegations {
+		if _, err := parseAddress(d.DelegatorAddress); err != nil {
+			return errors.Errorf("Delegation %d: %v", i, err)
+		}
+		validator, err := parseAddress(d.ValidatorAddress)
+		if err != nil {
+			return errors.Errorf("Delegation %d: %v", i, err)
+		}
+		if !addrs[validator] {
+			return errors.Errorf("Delegation %d is to %s which is not a genesis validator", i, d.ValidatorAddress)
+		}
+		amount, ok := new(big.Int).SetString(d.DelegateAmount, 10)
+		if !ok || amount.Sign() <= 0 {
+			return errors.Errorf("Delegation %d has an invalid delegate_amount %q", i, d.DelegateAmount)
+		}
+		if genDoc.IsExported() {
+			continue
+		}
+		if minStaking := utils.ToWei(genDoc.Params.MinStakingAmount); amount.Cmp(minStaking) < 0 {
+			return errors.Errorf("Delegation %d of %s wei is below the min_staking_amount %d", i, d.DelegateAmount, genDoc.Params.MinStakingAmount)
+		}
+		if r := room[validator]; r != nil {
+			if r.Sub(r, amount).Sign() < 0 {
+				return errors.Errorf("Delegation %d goes over the max_amount of the validator %s", i, d.ValidatorAddress)
+			}
+		}
+	}
+	return nil
+}
+
+// Stakes returns the coins in wei each account stakes at the genesis, which the
+// vm genesis must allocate to it
+func (genDoc *GenesisDoc) Stakes() map[common.Address]*big.Int {
+	stakes := make(map[common.Address]*big.Int)
+	add := func(addr common.Address, amount *big.Int) {
+		if stakes[addr] == nil {
+			stakes[addr] = new(big.Int)
+		}
+		stakes[addr].Add(stakes[addr], amount)
+	}
+	if genDoc.IsExported() {
+		return stakes
+	}
+	for _, v := range genDoc.Validators {
+		add(common.HexToAddress(v.Address), utils.ToWei(v.Shares))
+	}
+	for _, d := range genDoc.Delegations {
+		if amount, ok := new(big.Int).SetString(d.DelegateAmount, 10); ok {
+			add(common.HexToAddress(d.DelegatorAddress), amount)
+		}
+	}
+	return stakes
+}
+
+func parseAddress(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, errors.Errorf("%q is not an address", s)
+	}
+	addr := common.HexToAddress(s)
+	if addr == (common.Address{}) {
+		return addr, errors.Errorf("the zero address is not allowed")
+	}
+	return addr, nil
+}
+
 //------------------------------------------------------------
 // Make genesis state from file
 
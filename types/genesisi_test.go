@@ -0,0 +1,70 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+
+	"github.com/CyberMiles/travis/sdk"
+	"github.com/CyberMiles/travis/utils"
+)
+
+func genesisValidator(address string) GenesisValidator {
+	return GenesisValidator{
+		PubKey:    PubKey{ed25519.GenPrivKey().PubKey()},
+		Power:     "10",
+		Shares:    1000000,
+		Address:   address,
+		CompRate:  sdk.NewRat(2, 10),
+		MaxAmount: 10000000,
+	}
+}
+
+func TestGenesisValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	newGenDoc := func() *GenesisDoc {
+		return &GenesisDoc{
+			ChainID: "test",
+			Params:  utils.DefaultParams(),
+			Validators: []GenesisValidator{
+				genesisValidator("0x7eff122b94897ea5b0e2a9abf47b86337fafebdc"),
+				genesisValidator("0x77beb894fc9b0ed41231e51f128a347043960a9d"),
+			},
+			Delegations: []GenesisDelegation{{
+				DelegatorAddress: "0x34ef84c4a6b3a33d4a8fcfc8dd3cc5c1ec29c6a2",
+				ValidatorAddress: "0x77beb894fc9b0ed41231e51f128a347043960a9d",
+				DelegateAmount:   "1000000000000000000000",
+			}},
+		}
+	}
+	assert.Nil(newGenDoc().Validate())
+
+	for _, broken := range []func(g *GenesisDoc){
+		func(g *GenesisDoc) { g.Params.SelfStakingRatio = sdk.NewRat(2, 1) },
+		func(g *GenesisDoc) { g.Params.FoundationAddress = "0x7eff" },
+		func(g *GenesisDoc) { g.Validators[1].Address = "0x7eff122b94897ea5b0e2a9abf47b86337fafebdZ" },
+		func(g *GenesisDoc) { g.Validators[1].Address = g.Validators[0].Address },
+		func(g *GenesisDoc) { g.Validators[1].PubKey = g.Validators[0].PubKey },
+		func(g *GenesisDoc) { g.Validators[1].Shares = 999999 },
+		func(g *GenesisDoc) { g.Validators[1].CompRate = sdk.NewRat(1, 1) },
+		func(g *GenesisDoc) { g.Delegations[0].ValidatorAddress = "0x34ef84c4a6b3a33d4a8fcfc8dd3cc5c1ec29c6a2" },
+		func(g *GenesisDoc) { g.Delegations[0].DelegateAmount = "1e18" },
+		// below the min_staking_amount
+		func(g *GenesisDoc) { g.Delegations[0].DelegateAmount = "999000000000000000000" },
+		// over the max_amount along with the shares of the validator
+		func(g *GenesisDoc) { g.Delegations[0].DelegateAmount = "9000001000000000000000000" },
+		func(g *GenesisDoc) { g.Validators[1].Shares = g.Validators[1].MaxAmount },
+	} {
+		g := newGenDoc()
+		broken(g)
+		assert.NotNil(g.Validate())
+	}
+
+	stakes := newGenDoc().Stakes()
+	assert.Equal(3, len(stakes))
+	assert.Equal(utils.ToWei(1000000), stakes[common.HexToAddress("0x77beb894fc9b0ed41231e51f128a347043960a9d")])
+	assert.Equal(utils.ToWei(1000), stakes[common.HexToAddress("0x34ef84c4a6b3a33d4a8fcfc8dd3cc5c1ec29c6a2")])
+}
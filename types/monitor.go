@@ -1,7 +1,9 @@
 package types
 
 import (
+	"encoding/json"
 	"errors"
+	"strings"
 )
 
 // CmdInfo ...
@@ -10,6 +12,22 @@ type CmdInfo struct {
 	Version  string
 	DownloadURLs []string
 	MD5 string
+	SHA256 string
+}
+
+// NewCmdInfo creates the CmdInfo of a release, the checksum is taken as
+// SHA-256 if it is prefixed with "sha256:" or 64 hex digits long, otherwise as MD5
+func NewCmdInfo(name, version string, urls []string, checksum string) *CmdInfo {
+	info := &CmdInfo{Name: name, Version: version, DownloadURLs: urls}
+	switch {
+	case strings.HasPrefix(checksum, "sha256:"):
+		info.SHA256 = strings.TrimPrefix(checksum, "sha256:")
+	case len(checksum) == 64:
+		info.SHA256 = checksum
+	default:
+		info.MD5 = strings.TrimPrefix(checksum, "md5:")
+	}
+	return info
 }
 
 // Monitor ...
@@ -50,6 +68,33 @@ func (r *Monitor) Upgrade(info *CmdInfo, reply *MonitorResponse) error {
 	return nil
 }
 
+// Status reports the download status, the code is 0 if the release of info is ready to upgrade to
+func (r *Monitor) Status(info *CmdInfo, reply *MonitorResponse) error {
+	if info == nil || info.Name == "" {
+		return errors.New("CmdInfo can't be nil")
+	}
+	status := r.cmd.DownloadStatus()
+	if status.IsReady(info.ReleaseName()) {
+		reply.Code = 0
+	} else {
+		reply.Code = 1
+	}
+	reply.Msg, _ = json.Marshal(status)
+	return nil
+}
+
+// Health reports the state of the sub process, the code is 0 if it is running
+func (r *Monitor) Health(info *CmdInfo, reply *MonitorResponse) error {
+	health := r.cmd.Health()
+	if health.Running {
+		reply.Code = 0
+	} else {
+		reply.Code = 1
+	}
+	reply.Msg, _ = json.Marshal(health)
+	return nil
+}
+
 // Kill ...
 func (r *Monitor) Kill(info *CmdInfo, reply *MonitorResponse) error {
 	reply.Code = 0
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	assert := assert.New(t)
+
+	root, err := ioutil.TempDir("", "travis-rotate")
+	require.Nil(t, err)
+	defer os.RemoveAll(root)
+	path := filepath.Join(root, "logs", "travis.log")
+
+	read := func(name string) string {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+	write := func(r *RotatingFile, s string) {
+		n, err := r.Write([]byte(s))
+		assert.Nil(err)
+		assert.Equal(len(s), n)
+	}
+
+	r, err := NewRotatingFile(path, 10, 2)
+	require.Nil(t, err)
+	write(r, "12345678")
+	write(r, "ab")
+	assert.Equal("12345678ab", read(path))
+	assert.Equal("", read(path+".1"))
+
+	// rotated once full, the newest backup is path.1
+	write(r, "cdef")
+	assert.Equal("cdef", read(path))
+	assert.Equal("12345678ab", read(path+".1"))
+
+	// a write larger than the limit still goes into a single file
+	write(r, "0123456789abc")
+	assert.Equal("0123456789abc", read(path))
+	assert.Equal("cdef", read(path+".1"))
+	assert.Equal("12345678ab", read(path+".2"))
+
+	// the oldest backup is dropped
+	write(r, "x")
+	assert.Equal("x", read(path))
+	assert.Equal("0123456789abc", read(path+".1"))
+	assert.Equal("cdef", read(path+".2"))
+	_, err = os.Stat(path + ".3")
+	assert.True(os.IsNotExist(err))
+	assert.Nil(r.Close())
+
+	// reopened, the size of the existing file counts
+	r, err = NewRotatingFile(path, 10, 0)
+	require.Nil(t, err)
+	write(r, "yyyyyyyyy")
+	assert.Equal("xyyyyyyyyy", read(path))
+	// without backups the file starts over
+	write(r, "z")
+	assert.Equal("z", read(path))
+	assert.Equal("0123456789abc", read(path+".1"))
+	assert.Nil(r.Close())
+}
@@ -1,7 +1,6 @@
 package types
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -14,6 +13,17 @@ import (
 	"time"
 )
 
+var (
+	minRestartDelay = time.Second
+	maxRestartDelay = time.Minute
+	// a sub process crashing after running this long is restarted without delay
+	stableRunTime = 10 * time.Minute
+	// the sub process is killed if it does not exit in time once interrupted
+	stopTimeout = time.Minute
+	// how often the height of a new version is checked after an upgrade
+	upgradePollInterval = 5 * time.Second
+)
+
 // TravisCmd ...
 type TravisCmd struct {
 	Root     string
@@ -24,14 +34,45 @@ type TravisCmd struct {
 	NextArgs []string
 	// Env  []string
 	*sync.Mutex
-	DownloadChan chan *CmdInfo   //
+	DownloadChan chan *CmdInfo //
 	UpgradeChan  chan *CmdInfo //
 	KillChan     chan string   //
-	started      bool          // cmd.Start called, no error
-	stopped      bool          // Stop called
-	downloaded   bool          // donwload successfully
-	startTime    time.Time     // if started true
-	cmd          *exec.Cmd
+	// Output receives the output of the sub process, os.Stdout if nil
+	Output io.Writer
+	// the previous version is restored if the new one does not commit
+	// RollbackBlocks blocks within RollbackTimeout after an upgrade
+	RollbackBlocks  int64
+	RollbackTimeout time.Duration
+	// Height returns the latest block height of the sub process
+	Height     func() (int64, error)
+	started    bool          // cmd.Start called, no error
+	stopped    bool          // Stop called
+	downloaded bool          // donwload successfully
+	startTime  time.Time     // if started true
+	cmd        *exec.Cmd     //
+	exited     chan struct{} // closed once cmd exits
+	failures   int           // crashes in a row
+	restarts   int           //
+	lastExit   string        //
+	lastExitAt time.Time     //
+	upgrading  string        // the previous version while the upgrade is verified
+	rolledBack string        // the version the last rollback moved away from
+	statusMtx  sync.Mutex
+	status     DownloadStatus
+}
+
+// Health reports the state of the sub process
+type Health struct {
+	Release    string    `json:"release"`
+	Running    bool      `json:"running"`
+	Pid        int       `json:"pid"`
+	StartedAt  time.Time `json:"started_at"`
+	Height     int64     `json:"height"`
+	Restarts   int       `json:"restarts"`
+	LastExit   string    `json:"last_exit"`
+	LastExitAt time.Time `json:"last_exit_at"`
+	Upgrading  string    `json:"upgrading"`
+	RolledBack string    `json:"rolled_back"`
 }
 
 // NewTravisCmd create a new travis CMD
@@ -48,68 +89,132 @@ func NewTravisCmd(root string, name string, arg ...string) *TravisCmd {
 	}
 }
 
-// Start stop the sub travis process
+// Start starts the sub travis process, it is restarted with a backoff whenever it crashes
 func (c *TravisCmd) Start() error {
-	var stdoutBuf, stderrBuf bytes.Buffer
-	fullName := filepath.Join(c.Path, c.Name)
-	cmd := exec.Command(fullName, c.Args...)
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	c.Lock()
+	defer c.Unlock()
+	return c.start()
+}
 
-	stdoutIn, _ := cmd.StdoutPipe()
-	stderrIn, _ := cmd.StderrPipe()
+func (c *TravisCmd) start() error {
+	output := c.Output
+	if output == nil {
+		output = os.Stdout
+	}
 
-	var errStdout, errStderr error
-	stdout := io.MultiWriter(os.Stdout, &stdoutBuf)
-	stderr := io.MultiWriter(os.Stderr, &stderrBuf)
-	err := cmd.Start()
-	if err != nil {
-		log.Fatalf("cmd.Start() failed with '%s'\n", err)
+	cmd := exec.Command(filepath.Join(c.Path, c.Name), c.Args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := cmd.Start(); err != nil {
+		return err
 	}
 
+	exited := make(chan struct{})
 	c.started = true
+	c.stopped = false
 	c.startTime = time.Now()
 	c.cmd = cmd
-	c.downloaded = false
-	c.NextName = ""
-	c.NextArgs = nil
+	c.exited = exited
 
 	go func() {
-		_, errStdout = io.Copy(stdout, stdoutIn)
+		err := cmd.Wait()
+		close(exited)
+		c.onExit(cmd, err)
 	}()
+	return nil
+}
 
-	go func() {
-		_, errStderr = io.Copy(stderr, stderrIn)
-	}()
+// onExit restarts the sub process unless it was stopped on purpose
+func (c *TravisCmd) onExit(cmd *exec.Cmd, err error) {
+	c.Lock()
+	if c.cmd != cmd || c.stopped {
+		c.Unlock()
+		return
+	}
+	if time.Since(c.startTime) > stableRunTime {
+		c.failures = 0
+	}
+	c.started = false
+	c.cmd = nil
+	c.lastExit = exitReason(err)
+	c.lastExitAt = time.Now()
+	name := c.Name
+	c.Unlock()
 
-	go func() {
-		err = cmd.Wait()
-		if err != nil {
-			fmt.Printf("cmd.Run() failed with %s\n", err)
+	for {
+		delay := c.restartDelay()
+		log.Printf("%s exited unexpectedly (%s), restart in %s\n", name, exitReason(err), delay)
+		time.Sleep(delay)
+
+		c.Lock()
+		if c.started || c.stopped {
+			c.Unlock()
+			return
 		}
-		if errStdout != nil || errStderr != nil {
-			fmt.Printf("failed to capture stdout or stderr\n")
+		c.restarts++
+		err = c.start()
+		c.Unlock()
+		if err == nil {
+			return
 		}
-	}()
-	return nil
+	}
 }
 
-// Stop the sub travis process
-func (c *TravisCmd) Stop() error {
-	pro, err := os.FindProcess(c.cmd.Process.Pid)
-	if err != nil {
-		fmt.Printf("can not find rpocess:%d\n", c.cmd.Process.Pid)
-		return err
+// restartDelay doubles the delay on every crash in a row
+func (c *TravisCmd) restartDelay() time.Duration {
+	c.Lock()
+	defer c.Unlock()
+	delay := maxRestartDelay
+	if c.failures < 6 {
+		delay = minRestartDelay << uint(c.failures)
 	}
-	err = pro.Signal(syscall.SIGINT)
-	if err != nil {
-		fmt.Printf("failed to terminate sub-process: %s\n", c.cmd.Path)
-		return err
+	c.failures++
+	return delay
+}
+
+func exitReason(err error) string {
+	if err == nil {
+		return "exit status 0"
+	}
+	return err.Error()
+}
+
+// Stop stop the sub travis process
+func (c *TravisCmd) Stop() error {
+	c.Lock()
+	defer c.Unlock()
+	return c.stop()
+}
+
+// stop interrupts the sub process and waits for it to exit, it is killed after stopTimeout
+func (c *TravisCmd) stop() error {
+	c.stopped = true
+	if c.cmd == nil {
+		return nil
 	}
-	fmt.Printf("terminate sub-process sucessfully: %s\n", c.cmd.Path)
+	cmd, exited := c.cmd, c.exited
 	c.started = false
 	c.startTime = time.Time{}
 	c.cmd = nil
-	c.stopped = true
+
+	if err := cmd.Process.Signal(syscall.SIGINT); err != nil {
+		select {
+		case <-exited:
+			return nil
+		default:
+			fmt.Printf("failed to terminate sub-process: %s\n", cmd.Path)
+			return err
+		}
+	}
+	select {
+	case <-exited:
+	case <-time.After(stopTimeout):
+		fmt.Printf("sub-process does not exit in %s, kill it: %s\n", stopTimeout, cmd.Path)
+		cmd.Process.Kill()
+		<-exited
+	}
+	fmt.Printf("terminate sub-process sucessfully: %s\n", cmd.Path)
 	return nil
 }
 
@@ -118,59 +223,184 @@ func (c *TravisCmd) Restart() error {
 	c.Lock()
 	defer c.Unlock()
 	// stop the old
-	if err := c.Stop(); err != nil {
+	if err := c.stop(); err != nil {
 		return err
 	}
-	c.Start()
-	return nil
+	return c.start()
 }
 
-// Upgrade upgrade to new version travis
+// Upgrade upgrade to new version travis, the previous version is restored
+// if the new one fails to come up
 func (c *TravisCmd) Upgrade(cmdInfo *CmdInfo) error {
 	c.Lock()
 	defer c.Unlock()
-	// TODO: need sleep a while to wait something finish ?
-	time.Sleep(time.Second * 1)
 
-	// stop the old
-	if err := c.Stop(); err != nil {
+	if !c.downloaded || c.NextName != cmdInfo.ReleaseName() {
+		return errors.New("no new version travis get ready")
+	}
+	if _, err := os.Stat(filepath.Join(c.Path, c.NextName)); err != nil {
 		return err
 	}
 
-	if !c.downloaded || c.NextName == "" {
-		return errors.New("no new version travis get ready")
+	// let the old one finish the commit which requested the upgrade
+	time.Sleep(time.Second * 1)
+	height := c.height()
+
+	// stop the old
+	if err := c.stop(); err != nil {
+		return err
 	}
 
 	// using the new version
+	prev := c.Name
 	c.Name = c.NextName
+	c.NextName = ""
+	c.NextArgs = nil
+	c.downloaded = false
+	c.failures = 0
+	c.upgrading = prev
+	if err := c.start(); err != nil {
+		log.Printf("Failed to start %s: %s\n", c.Name, err)
+		c.rollback(prev)
+		return err
+	}
 
-	c.Start()
+	go c.verifyUpgrade(c.Name, prev, height)
 	return nil
 }
 
-// Download download the new version travis as specified
-func (c *TravisCmd) Download(cmdInfo *CmdInfo) error {
+// verifyUpgrade rolls back to the previous version unless the new one
+// commits RollbackBlocks blocks within RollbackTimeout
+func (c *TravisCmd) verifyUpgrade(release, prev string, from int64) {
+	up := c.Height == nil || c.RollbackBlocks <= 0
+	deadline := time.Now().Add(c.RollbackTimeout)
+	for !up && time.Now().Before(deadline) {
+		time.Sleep(upgradePollInterval)
+		h, err := c.Height()
+		if err != nil {
+			continue
+		}
+		// the height at the upgrade is unknown, count from the first one seen
+		if from <= 0 {
+			from = h
+		}
+		if h >= from+c.RollbackBlocks {
+			log.Printf("%s committed block %d\n", release, h)
+			up = true
+		}
+	}
+
 	c.Lock()
 	defer c.Unlock()
+	// stopped or upgraded again meanwhile
+	if c.Name != release || c.upgrading != prev {
+		return
+	}
+	if up {
+		c.upgrading = ""
+		return
+	}
+	log.Printf("%s did not commit %d blocks in %s, roll back to %s\n", release, c.RollbackBlocks, c.RollbackTimeout, prev)
+	c.rollback(prev)
+}
+
+// rollback restores the previous version
+func (c *TravisCmd) rollback(prev string) {
+	if err := c.stop(); err != nil {
+		log.Printf("Failed to stop %s: %s\n", c.Name, err)
+	}
+	c.rolledBack = c.Name
+	c.Name = prev
+	c.upgrading = ""
+	c.failures = 0
+	if err := c.start(); err != nil {
+		log.Printf("Failed to start %s: %s\n", c.Name, err)
+	}
+}
 
-	if c.downloaded && c.NextName == cmdInfo.ReleaseName() {
+func (c *TravisCmd) height() int64 {
+	if c.Height == nil {
+		return 0
+	}
+	h, err := c.Height()
+	if err != nil {
+		return 0
+	}
+	return h
+}
+
+// Health returns the state of the sub process
+func (c *TravisCmd) Health() Health {
+	c.Lock()
+	h := Health{
+		Release:    c.Name,
+		Running:    c.started,
+		StartedAt:  c.startTime,
+		Restarts:   c.restarts,
+		LastExit:   c.lastExit,
+		LastExitAt: c.lastExitAt,
+		Upgrading:  c.upgrading,
+		RolledBack: c.rolledBack,
+	}
+	if c.cmd != nil {
+		h.Pid = c.cmd.Process.Pid
+	}
+	c.Unlock()
+
+	h.Height = c.height()
+	return h
+}
+
+// Download download the new version travis as specified
+func (c *TravisCmd) Download(cmdInfo *CmdInfo) error {
+	release := cmdInfo.ReleaseName()
+
+	c.Lock()
+	if c.downloaded && c.NextName == release {
+		c.Unlock()
 		log.Println("same version already exist")
 		return nil
 	}
+	c.Unlock()
+
+	c.setDownloadStatus(DownloadStatus{Release: release, State: DownloadInProgress})
+	if err := os.MkdirAll(c.Path, 0755); err != nil {
+		c.setDownloadStatus(DownloadStatus{Release: release, State: DownloadFailed, Error: err.Error()})
+		return err
+	}
 
-	// TODO: automatically download comming soon ...
-	//if err := exec.Command("cp", "-f", filepath.Join(c.Path, "travis"), filepath.Join(c.Path, name)).Run(); err != nil {
-	//	return err
-	//}
-	log.Println("download does not happen automatically, please copy it manually")
+	// the supervisor keeps running the sub process while downloading
+	url, err := fetchRelease(cmdInfo, filepath.Join(c.Path, release))
+	if err != nil {
+		c.setDownloadStatus(DownloadStatus{Release: release, State: DownloadFailed, Error: err.Error()})
+		return err
+	}
+	log.Printf("%s downloaded from %s\n", release, url)
+	c.setDownloadStatus(DownloadStatus{Release: release, State: DownloadReady, URL: url})
 
 	// using the new version
-	c.NextName = cmdInfo.ReleaseName()
+	c.Lock()
+	c.NextName = release
 	c.downloaded = true
+	c.Unlock()
 
 	return nil
 }
 
+// DownloadStatus returns the status of the last download
+func (c *TravisCmd) DownloadStatus() DownloadStatus {
+	c.statusMtx.Lock()
+	defer c.statusMtx.Unlock()
+	return c.status
+}
+
+func (c *TravisCmd) setDownloadStatus(status DownloadStatus) {
+	c.statusMtx.Lock()
+	defer c.statusMtx.Unlock()
+	status.UpdatedAt = time.Now()
+	c.status = status
+}
+
 // Cmd ...
 func (c *TravisCmd) Cmd() *exec.Cmd {
 	return c.cmd
@@ -188,4 +418,3 @@ func (c *TravisCmd) Kill() error {
 	}
 	return p.Signal(syscall.SIGTERM)
 }
-
@@ -0,0 +1,151 @@
+package types
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTravis writes a shell script standing for a travis release into the bin directory
+func fakeTravis(t *testing.T, root, name, script string, mode os.FileMode) {
+	bin := filepath.Join(root, "bin")
+	require.Nil(t, os.MkdirAll(bin, 0755))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"+script+"\n"), mode))
+}
+
+func waitFor(t *testing.T, msg string, cond func() bool) {
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for " + msg)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTravisCmdRestartDelay(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewTravisCmd("", "travis")
+	for i := uint(0); i < 6; i++ {
+		assert.Equal(minRestartDelay<<i, c.restartDelay())
+	}
+	assert.Equal(maxRestartDelay, c.restartDelay())
+	assert.Equal(maxRestartDelay, c.restartDelay())
+}
+
+func TestTravisCmdCrashRestart(t *testing.T) {
+	assert := assert.New(t)
+	defer func(min, max time.Duration) {
+		minRestartDelay, maxRestartDelay = min, max
+	}(minRestartDelay, maxRestartDelay)
+	minRestartDelay, maxRestartDelay = 10*time.Millisecond, 100*time.Millisecond
+
+	root, err := ioutil.TempDir("", "travis-cmd")
+	require.Nil(t, err)
+	defer os.RemoveAll(root)
+	runs := filepath.Join(root, "runs")
+	fakeTravis(t, root, "travis", "echo run >> "+runs+"\nexit 3", 0755)
+
+	countRuns := func() int {
+		b, _ := ioutil.ReadFile(runs)
+		return strings.Count(string(b), "run")
+	}
+
+	c := NewTravisCmd(root, "travis")
+	c.Output = ioutil.Discard
+	assert.Nil(c.Start())
+	waitFor(t, "the crashed process to be restarted", func() bool {
+		return countRuns() >= 4
+	})
+
+	h := c.Health()
+	assert.True(h.Restarts >= 3)
+	assert.Equal("exit status 3", h.LastExit)
+	c.Lock()
+	assert.True(c.failures >= 3, "the delay grows on every crash in a row")
+	c.Unlock()
+
+	// no restart once stopped
+	assert.Nil(c.Stop())
+	n := countRuns()
+	time.Sleep(10 * maxRestartDelay)
+	assert.Equal(n, countRuns())
+	assert.False(c.Health().Running)
+}
+
+func TestTravisCmdUpgradeRollback(t *testing.T) {
+	assert := assert.New(t)
+	defer func(d time.Duration) {
+		upgradePollInterval = d
+	}(upgradePollInterval)
+	upgradePollInterval = 10 * time.Millisecond
+
+	root, err := ioutil.TempDir("", "travis-cmd")
+	require.Nil(t, err)
+	defer os.RemoveAll(root)
+	fakeTravis(t, root, "travis_v1", "exec sleep 60", 0755)
+	fakeTravis(t, root, "travis_v2", "exec sleep 60", 0755)
+	fakeTravis(t, root, "travis_v3", "exec sleep 60", 0644)
+
+	// the height moves by step on every call
+	var height, step int64
+	c := NewTravisCmd(root, "travis_v1")
+	c.Output = ioutil.Discard
+	c.RollbackBlocks = 2
+	c.RollbackTimeout = 200 * time.Millisecond
+	c.Height = func() (int64, error) {
+		return atomic.AddInt64(&height, atomic.LoadInt64(&step)), nil
+	}
+	assert.Nil(c.Start())
+	defer c.Stop()
+	pid := c.Health().Pid
+
+	ready := func(version string) *CmdInfo {
+		info := &CmdInfo{Name: "travis", Version: version}
+		c.Lock()
+		c.NextName = info.ReleaseName()
+		c.downloaded = true
+		c.Unlock()
+		return info
+	}
+
+	// the new version does not commit any block, the previous one is restored
+	assert.Nil(c.Upgrade(ready("v2")))
+	h := c.Health()
+	assert.Equal("travis_v2", h.Release)
+	assert.Equal("travis_v1", h.Upgrading)
+	assert.NotEqual(pid, h.Pid)
+	waitFor(t, "the rollback", func() bool {
+		return c.Health().RolledBack == "travis_v2"
+	})
+	h = c.Health()
+	assert.Equal("travis_v1", h.Release)
+	assert.Equal("", h.Upgrading)
+	assert.True(h.Running)
+
+	// the new version can not be started
+	assert.NotNil(c.Upgrade(ready("v3")))
+	h = c.Health()
+	assert.Equal("travis_v1", h.Release)
+	assert.Equal("travis_v3", h.RolledBack)
+	assert.True(h.Running)
+
+	// the new version commits the blocks in time
+	c.RollbackTimeout = 10 * time.Second
+	atomic.StoreInt64(&step, 1)
+	assert.Nil(c.Upgrade(ready("v2")))
+	waitFor(t, "the upgrade to be verified", func() bool {
+		return c.Health().Upgrading == ""
+	})
+	h = c.Health()
+	assert.Equal("travis_v2", h.Release)
+	assert.True(h.Running)
+}
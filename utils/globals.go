@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"encoding/json"
 	"math"
 	"math/big"
+	"strings"
 
 	"github.com/CyberMiles/travis/sdk"
 	"github.com/ethereum/go-ethereum/common"
@@ -180,17 +182,71 @@ func shouldBePacked(timestamp, lastTs int64) bool {
 	return false
 }
 
-func IsEthTx(tx *types.Transaction) bool {
-	zero := big.NewInt(0)
-	return tx.Data() == nil ||
-		tx.GasPrice().Cmp(zero) != 0 ||
-		tx.Gas() != 0 ||
-		tx.Value().Cmp(zero) != 0 ||
-		tx.To() != nil
+// IsEthTx tells the ethereum txs from the travis ones of the block, which are contract
+// creations without value carrying a stake, governance, sponsor or multisig tx as data.
+// The gas limit and gas price are not considered since travis txs declare them too,
+// before declared_gas_height the travis txs are the ones declaring neither of them.
+func IsEthTx(tx *types.Transaction, height int64) bool {
+	if !IsDeclaredGas(height) {
+		zero := big.NewInt(0)
+		return tx.Data() == nil ||
+			tx.GasPrice().Cmp(zero) != 0 ||
+			tx.Gas() != 0 ||
+			tx.Value().Cmp(zero) != 0 ||
+			tx.To() != nil
+	}
+	return tx.To() != nil ||
+		tx.Value().Sign() != 0 ||
+		!IsTravisTxData(tx.Data())
+}
+
+// IsDeclaredGas tells whether the travis txs of the block are charged at their
+// declared gas limit and gas price
+func IsDeclaredGas(height int64) bool {
+	h := GetParams().DeclaredGasHeight
+	return h > 0 && height >= int64(h)
+}
+
+// IsTravisTxData checks whether the data is a json encoded travis tx
+func IsTravisTxData(data []byte) bool {
+	if len(data) == 0 || data[0] != '{' {
+		return false
+	}
+	var tx struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return false
+	}
+	for _, module := range travisTxModules {
+		if strings.HasPrefix(tx.Type, module+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// travisTxModules are the modules handling the travis txs, the prefixes of their tx types
+var travisTxModules = []string{"stake", "governance", "sponsor", "multisig"}
+
+// IsFreeGasTx tells the zero gas price contract calls exceeding the gas limit of the low-price transactions
+func IsFreeGasTx(tx *types.Transaction) bool {
+	return tx.GasPrice().Sign() == 0 &&
+		tx.Gas() > GetParams().LowPriceTxGasLimit &&
+		tx.To() != nil &&
+		len(tx.Data()) > 0
+}
+
+// IsSponsoredTx tells whether the gas of the tx is paid by the sponsorship of the callee contract,
+// that is a free gas tx once the sponsorships are enabled by the sponsorship_gas parameter.
+// Before, the free gas txs only need the callee to hold the cost of the tx at the default gas price.
+func IsSponsoredTx(tx *types.Transaction) bool {
+	return GetParams().SponsorshipGas > 0 && IsFreeGasTx(tx)
 }
 
 func CalGasFee(gasUsed uint64, gasPrice uint64) sdk.Int {
-	return sdk.NewInt(int64(gasUsed)).Mul(sdk.NewInt(int64(gasPrice)))
+	fee := new(big.Int).SetUint64(gasUsed)
+	return sdk.NewIntFromBigInt(fee.Mul(fee, new(big.Int).SetUint64(gasPrice)))
 }
 
 var (
@@ -209,4 +265,5 @@ var (
 	MintAccount    = common.HexToAddress("0000000000000000000000000000000000000000")
 	HoldAccount    = common.HexToAddress("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF")
 	GovHoldAccount = common.HexToAddress("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF")
+	SponsorAccount = common.HexToAddress("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFE") // escrow of the gas sponsorships
 )
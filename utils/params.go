@@ -2,9 +2,12 @@ package utils
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"strconv"
 
+	"github.com/ethereum/go-ethereum/common"
+
 	"github.com/CyberMiles/travis/sdk"
 )
 
@@ -35,10 +38,19 @@ type Params struct {
 	CubePubKeys                            string  `json:"cube_pub_keys" type:"json"`
 	LowPriceTxGasLimit                     uint64  `json:"low_price_tx_gas_limit" type:"uint"`
 	LowPriceTxSlotsCap                     int     `json:"low_price_tx_slots_cap" type:"int"`
+	LowPriceTxSenderLimit                  int     `json:"low_price_tx_sender_limit" type:"int"`
+	LowPriceTxSenderWindow                 uint64  `json:"low_price_tx_sender_window" type:"uint"`
+	LowPriceTxMaxAge                       uint64  `json:"low_price_tx_max_age" type:"uint"`
+	SponsorshipGas                         uint64  `json:"sponsorship_gas" type:"uint"`
+	SponsorshipUserWindow                  uint64  `json:"sponsorship_user_window" type:"uint"`
 	FoundationAddress                      string  `json:"foundation_address"`
 	CalStakeInterval                       uint64  `json:"cal_stake_interval" type:"uint"`
 	CalVPInterval                          uint64  `json:"cal_vp_interval" type:"uint"`
 	CalAverageStakingDateInterval          uint64  `json:"cal_avg_staking_date_interval" type:"uint"`
+	StateProofHeight                       uint64  `json:"state_proof_height" type:"uint"` // height the stake and governance state is provable from, 0 means never
+	MultisigGas                            uint64  `json:"multisig_gas" type:"uint"`
+	UpgradeReadinessHeight                 uint64  `json:"upgrade_readiness_height" type:"uint"` // height the upgrade proposals need 2/3 of the voting power ready from, 0 means never
+	DeclaredGasHeight                      uint64  `json:"declared_gas_height" type:"uint"`      // height the travis txs are charged at their declared gas limit and gas price from, 0 means never
 }
 
 func DefaultParams() *Params {
@@ -69,11 +81,60 @@ func DefaultParams() *Params {
 		CubePubKeys:                            `[{"cube_batch":"01","pub_key":"-----BEGIN PUBLIC KEY-----\nMIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQCiWpvDnwYFTqgSWPlA3VO8u+Yv\n9r8QGlRaYZFszUZEXUQxquGlFexMSVyFeqYjIokfPOEHHx2voqWgi3FKKlp6dkxw\nApP3T22y7Epqvtr+EfNybRta15snccZy47dY4UcmYxbGWFTaL66tz22pCAbjFrxY\n3IxaPPIjDX+FiXdJWwIDAQAB\n-----END PUBLIC KEY-----"},{"cube_batch":"02","pub_key":"-----BEGIN PUBLIC KEY-----\nMIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQDQ8FL6/9zul+X7bFSRiWAzFiAE\n9vHYbClEHwlC7zUZ/JWzU7UT5S2qnYsseYF2WFjJtrGwHRAlTUyPtCpxV8f1uJsI\nl+/N9l6torUHwkhhib1catUSd/T72ltjvVyyg5LQjtRsskFnv3wM/yxYotrgnOs+\ndRpU6WI5XPCIyZqsGwIDAQAB\n-----END PUBLIC KEY-----"},{"cube_batch":"05","pub_key":"-----BEGIN PUBLIC KEY-----\nMIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQCZ7Fw+1ddvy5OPFftbea0MxewW\nKUTb/E7B4/MHvLz2h7f7snyveFwxxj7QwxaCoVxobEq6AigIlUFUXLM8Y598/jts\nTaN+jh4xdoQN7qKwrbz1MWGf58Aa78Vnoj54B7V0LSajVbLJSZNUEI/24HLcG2iN\nTD3dSvH0ARvRJJ9hZQIDAQAB\n-----END PUBLIC KEY-----"},{"cube_batch":"06","pub_key":"-----BEGIN PUBLIC KEY-----\nMIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQCbNyWzuQ8Vrgrf9no9woaqgifc\njxfpvpuREoGNBOzOMl9BpyTa45t2ZeigE+xLaTZJc7dVTMQus8ik1b2qQcmrdViR\nbFx2P7tPg5z0DlDVXjq2G8Q3mP0WBEhGzyfycUmaT+yXoLu/UzGfFhr5nVztkUVD\noOHnTtsKCKQekuY3YwIDAQAB\n-----END PUBLIC KEY-----"}]`,
 		LowPriceTxGasLimit:                     500000, // Maximum gas limit for low-price transaction
 		LowPriceTxSlotsCap:                     100,    // Maximum number of low-price transaction slots per block
+		LowPriceTxSenderLimit:                  10,     // Maximum number of low-price transactions per sender within the window, 0 means no limit
+		LowPriceTxSenderWindow:                 60,     // Number of blocks the low-price transactions per sender are counted over
+		LowPriceTxMaxAge:                       30,     // Number of blocks a low-price transaction can wait in the mempool, 0 means forever
+		SponsorshipGas:                         21000,  // gas setting for the sponsorship transactions
+		SponsorshipUserWindow:                  8640,   // Number of blocks (one day) the sponsored fee per user is counted over
 		FoundationAddress:                      "0x7eff122b94897ea5b0e2a9abf47b86337fafebdc",
 		CalStakeInterval:                       1, // calculate stake interval, default per block
 		CalVPInterval:                          1, // calculate voting power interval, default per block
 		CalAverageStakingDateInterval:          24 * 3600 / 10,
+		StateProofHeight:                       1,
+		MultisigGas:                            21000, // gas setting for the multisig transactions, the executed tx is charged on its own
+		UpgradeReadinessHeight:                 1,
+		DeclaredGasHeight:                      1,
+	}
+}
+
+// Validate checks the params are in their ranges
+func (p *Params) Validate() error {
+	if p.MaxVals == 0 {
+		return fmt.Errorf("max_vals must be positive")
+	}
+	for name, r := range map[string]sdk.Rat{
+		"self_staking_ratio":           p.SelfStakingRatio,
+		"inflation_rate":               p.InflationRate,
+		"validator_size_threshold":     p.ValidatorSizeThreshold,
+		"validators_block_award_ratio": p.ValidatorsBlockAwardRatio,
+		"slash_ratio":                  p.SlashRatio,
+	} {
+		if r.IsNil() || r.LT(sdk.ZeroRat) || r.GT(sdk.OneRat) {
+			return fmt.Errorf("%s must be between 0 and 1", name)
+		}
+	}
+	for name, v := range map[string]uint64{
+		"unstake_waiting_period":        p.UnstakeWaitingPeriod,
+		"proposal_expire_period":        p.ProposalExpirePeriod,
+		"gas_price":                     p.GasPrice,
+		"cal_stake_interval":            p.CalStakeInterval,
+		"cal_vp_interval":               p.CalVPInterval,
+		"cal_avg_staking_date_interval": p.CalAverageStakingDateInterval,
+	} {
+		if v == 0 {
+			return fmt.Errorf("%s must be positive", name)
+		}
+	}
+	if p.MinStakingAmount < 0 || p.MaxSlashBlocks < 0 || p.LowPriceTxSlotsCap < 0 || p.LowPriceTxSenderLimit < 0 {
+		return fmt.Errorf("min_staking_amount, max_slash_blocks and the low-price tx limits must not be negative")
+	}
+	if !CheckParamType("cube_pub_keys", p.CubePubKeys) {
+		return fmt.Errorf("cube_pub_keys must be a json array")
+	}
+	if !common.IsHexAddress(p.FoundationAddress) {
+		return fmt.Errorf("foundation_address %q is not an address", p.FoundationAddress)
 	}
+	return nil
 }
 
 var (
@@ -82,6 +143,12 @@ var (
 	AwardInfosKey       = []byte{0x02} // key for award infos
 	AbsentValidatorsKey = []byte{0x03} // key for absent validators
 	PubKeyUpdatesKey    = []byte{0x04} // key for absent validators
+	SponsorshipKey      = []byte{0x05} // key prefix for gas sponsorships
+	DbHashKey           = []byte{0x06} // key for the hash of the sqlite db, proven along with the mirrored rows
+	CandidateKey        = []byte{0x07} // key prefix for the mirrored candidates
+	DelegationKey       = []byte{0x08} // key prefix for the mirrored delegations
+	ProposalKey         = []byte{0x09} // key prefix for the mirrored proposals
+	MultisigKey         = []byte{0x0a} // key prefix for multisig accounts
 	dirty               = false
 	params              = new(Params)
 )
@@ -2,6 +2,7 @@ package ethereum
 
 import (
 	"bytes"
+	"fmt"
 	"math/big"
 	"sync"
 
@@ -228,7 +229,7 @@ func (ws *workState) deliverTx(blockchain *core.BlockChain, config *eth.Config,
 	ws.receipts = append(ws.receipts, receipt)
 	ws.allLogs = append(ws.allLogs, logs...)
 
-	return abciTypes.ResponseDeliverTx{Code: abciTypes.CodeTypeOK}
+	return abciTypes.ResponseDeliverTx{Code: abciTypes.CodeTypeOK, GasUsed: int64(usedGas)}
 }
 
 // Commit the ethereum state, update the header, make a new block and add it to
@@ -311,13 +312,15 @@ func (ws *workState) commit(blockchain *core.BlockChain, db ethdb.Database, rece
 		case gov.UPGRADE_PROGRAM_PROPOSAL:
 			if proposal.Result == "Approved" {
 				// Upgrade program command to new version
-				gov.UpgradeProgramCmd(proposal)
+				if msg := upgradeProgram(proposal); msg != "" {
+					gov.UpdateProposalResult(proposal.Id, proposal.Result, msg, proposal.ResultBlockHeight)
+				}
 			} else {
 				switch gov.CheckProposal(pid, nil) {
 				case "approved":
 					// Upgrade program command to new version
-					gov.UpgradeProgramCmd(proposal)
-					gov.ProposalReactor{proposal.Id, currentHeight, "Approved"}.React("success", "")
+					msg := upgradeProgram(proposal)
+					gov.ProposalReactor{proposal.Id, currentHeight, "Approved"}.React("success", msg)
 				case "rejected":
 					gov.ProposalReactor{proposal.Id, currentHeight, "Rejected"}.React("success", "")
 				default:
@@ -453,3 +456,28 @@ func calcGasLimit(parent *types.Block) uint64 {
 	var gl uint64 = 8192000000 // 8192m
 	return gl
 }
+
+// upgradeProgram switches to the new program version of the proposal when the validators
+// ready to run it hold 2/3 of the voting power, otherwise it returns why the node stays.
+// The proposals created before upgrade_readiness_height switch regardless.
+func upgradeProgram(proposal *gov.Proposal) string {
+	if h := utils.GetParams().UpgradeReadinessHeight; h == 0 || proposal.BlockHeight < int64(h) {
+		if err := gov.UpgradeProgramCmd(proposal); err != nil {
+			log.Error("Upgrade program failed", "proposal", proposal.Id, "err", err)
+		}
+		return ""
+	}
+
+	status := gov.CheckUpgradeReadiness(proposal)
+	if !status.ThresholdMet {
+		log.Warn("Not enough validators are ready to upgrade", "proposal", proposal.Id,
+			"ready", status.ReadyPower, "required", status.RequiredPower, "total", status.TotalPower)
+		return fmt.Sprintf("Not enough validators are ready to upgrade, ready power %d, required %d of %d",
+			status.ReadyPower, status.RequiredPower, status.TotalPower)
+	}
+
+	if err := gov.UpgradeProgramCmd(proposal); err != nil {
+		log.Error("Upgrade program failed", "proposal", proposal.Id, "err", err)
+	}
+	return ""
+}